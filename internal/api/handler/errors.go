@@ -18,6 +18,13 @@ const (
 	CodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	CodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	CodeNotFound    ErrorCode = "NOT_FOUND"
+	CodeInvalid     ErrorCode = "INVALID_REQUEST"
+	CodeNotEnough   ErrorCode = "NOT_ENOUGH_REVIEWERS"
+	CodeNotApproved ErrorCode = "NOT_APPROVED"
+	CodePRNotOpen   ErrorCode = "PR_NOT_OPEN"
+	CodeTransition  ErrorCode = "INVALID_TRANSITION"
+	CodeOtherTeam   ErrorCode = "USER_IN_OTHER_TEAM"
+	CodeTeamHasPRs  ErrorCode = "TEAM_HAS_OPEN_PRS"
 )
 
 type ErrorResponse struct {
@@ -90,9 +97,69 @@ func mapError(err error) (int, ErrorResponse) {
 			},
 		}
 
+	case errors.Is(err, domain.ErrNotEnoughReviewers):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeNotEnough,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrNotEnoughApprovals):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeNotApproved,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrPRNotOpen):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodePRNotOpen,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeTransition,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrUserInOtherTeam):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeOtherTeam,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrTeamHasOpenPRs):
+		return http.StatusConflict, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeTeamHasPRs,
+				Message: err.Error(),
+			},
+		}
+
+	case errors.Is(err, domain.ErrInvalidRequest):
+		return http.StatusBadRequest, ErrorResponse{
+			Error: ErrorDetail{
+				Code:    CodeInvalid,
+				Message: err.Error(),
+			},
+		}
+
 	case errors.Is(err, domain.ErrTeamNotFound),
 		errors.Is(err, domain.ErrUserNotFound),
-		errors.Is(err, domain.ErrPRNotFound):
+		errors.Is(err, domain.ErrPRNotFound),
+		errors.Is(err, domain.ErrRuleNotFound),
+		errors.Is(err, domain.ErrSubscriptionNotFound),
+		errors.Is(err, domain.ErrIdentityNotFound),
+		errors.Is(err, domain.ErrSyncTaskNotFound):
 		return http.StatusNotFound, ErrorResponse{
 			Error: ErrorDetail{
 				Code:    CodeNotFound,
@@ -118,5 +185,16 @@ func isDomainError(err error) bool {
 		errors.Is(err, domain.ErrPRNotFound) ||
 		errors.Is(err, domain.ErrPRMerged) ||
 		errors.Is(err, domain.ErrNotAssigned) ||
-		errors.Is(err, domain.ErrNoCandidate)
+		errors.Is(err, domain.ErrNoCandidate) ||
+		errors.Is(err, domain.ErrInvalidRequest) ||
+		errors.Is(err, domain.ErrNotEnoughReviewers) ||
+		errors.Is(err, domain.ErrRuleNotFound) ||
+		errors.Is(err, domain.ErrNotEnoughApprovals) ||
+		errors.Is(err, domain.ErrPRNotOpen) ||
+		errors.Is(err, domain.ErrInvalidTransition) ||
+		errors.Is(err, domain.ErrUserInOtherTeam) ||
+		errors.Is(err, domain.ErrTeamHasOpenPRs) ||
+		errors.Is(err, domain.ErrSubscriptionNotFound) ||
+		errors.Is(err, domain.ErrIdentityNotFound) ||
+		errors.Is(err, domain.ErrSyncTaskNotFound)
 }
@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/pkg/webhook"
+	"github.com/ZertGraf/avito-test/internal/service"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"net/http"
+)
+
+// maxIntegrationBodyBytes limits the size of an incoming webhook, as git hostings do.
+const maxIntegrationBodyBytes = 25 << 20
+
+const (
+	githubSignatureHeader = "X-Hub-Signature-256"
+	githubEventHeader     = "X-GitHub-Event"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+	gitlabEventHeader     = "X-Gitlab-Event"
+)
+
+// actionIgnored is reported for webhooks that don't change pull requests.
+const actionIgnored = "ignored"
+
+// IntegrationConfig holds secrets shared with git hostings.
+// An empty secret disables the corresponding endpoint.
+type IntegrationConfig struct {
+	GitHubSecret string
+	GitLabToken  string
+}
+
+type IntegrationHandler struct {
+	integrationService  *service.IntegrationService
+	reviewerSyncService *service.ReviewerSyncService
+	config              IntegrationConfig
+	logger              *logger.Logger
+}
+
+func NewIntegrationHandler(
+	integrationService *service.IntegrationService,
+	reviewerSyncService *service.ReviewerSyncService,
+	config IntegrationConfig,
+	logger *logger.Logger,
+) *IntegrationHandler {
+	return &IntegrationHandler{
+		integrationService:  integrationService,
+		reviewerSyncService: reviewerSyncService,
+		config:              config,
+		logger:              logger.Component("handler/integration"),
+	}
+}
+
+func (h *IntegrationHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/health", healthCheck)
+	r.Post("/github", h.GitHub)
+	r.Post("/gitlab", h.GitLab)
+	r.Post("/identities/add", h.AddIdentity)
+	r.Get("/identities/list", h.ListIdentities)
+	r.Post("/identities/remove", h.RemoveIdentity)
+	r.Get("/sync/failed", h.ListFailedSyncs)
+	r.Post("/sync/retry", h.RetrySync)
+
+	return r
+}
+
+type IngestResponse struct {
+	Action string              `json:"action"` // применённое действие или ignored
+	PR     *domain.PullRequest `json:"pr,omitempty"`
+}
+
+// GitHub applies a pull_request webhook signed with the shared secret.
+// Other events, including ping, are acknowledged and ignored.
+func (h *IntegrationHandler) GitHub(w http.ResponseWriter, r *http.Request) {
+	if h.config.GitHubSecret == "" {
+		h.logger.Warn("github webhook received, but integration is not configured")
+		http.Error(w, "github integration is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBodyBytes))
+	if err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !webhook.Verify(h.config.GitHubSecret, body, r.Header.Get(githubSignatureHeader)) {
+		h.logger.Warn("invalid github signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Header.Get(githubEventHeader) != "pull_request" {
+		h.writeIngest(w, actionIgnored, nil)
+		return
+	}
+
+	event, err := parseGitHubEvent(body)
+	if err != nil {
+		h.logger.Warn("invalid github payload", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.apply(w, r, event)
+}
+
+// GitLab applies a merge request webhook carrying the shared token.
+// Other events are acknowledged and ignored.
+func (h *IntegrationHandler) GitLab(w http.ResponseWriter, r *http.Request) {
+	if h.config.GitLabToken == "" {
+		h.logger.Warn("gitlab webhook received, but integration is not configured")
+		http.Error(w, "gitlab integration is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !validGitLabToken(h.config.GitLabToken, r.Header.Get(gitlabTokenHeader)) {
+		h.logger.Warn("invalid gitlab token")
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBodyBytes))
+	if err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get(gitlabEventHeader) != "Merge Request Hook" {
+		h.writeIngest(w, actionIgnored, nil)
+		return
+	}
+
+	event, err := parseGitLabEvent(body)
+	if err != nil {
+		h.logger.Warn("invalid gitlab payload", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.apply(w, r, event)
+}
+
+// validGitLabToken reports whether the webhook token matches the configured one.
+func validGitLabToken(expected, token string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// apply passes a parsed event to the service; nil event means the action is ignored.
+func (h *IntegrationHandler) apply(w http.ResponseWriter, r *http.Request, event *domain.ExternalPREvent) {
+	if event == nil {
+		h.writeIngest(w, actionIgnored, nil)
+		return
+	}
+
+	pr, err := h.integrationService.HandlePREvent(r.Context(), *event)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	h.writeIngest(w, string(event.Action), pr)
+}
+
+func (h *IntegrationHandler) writeIngest(w http.ResponseWriter, action string, pr *domain.PullRequest) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := IngestResponse{Action: action, PR: pr}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// githubPullRequestEvent is the part of GitHub's pull_request webhook payload we use.
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string      `json:"title"`
+		Draft  bool        `json:"draft"`
+		Merged bool        `json:"merged"`
+		User   githubLogin `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender githubLogin `json:"sender"`
+}
+
+type githubLogin struct {
+	Login string `json:"login"`
+}
+
+// parseGitHubEvent maps a pull_request payload onto an event.
+// Returns nil event for actions that don't change the pull request status.
+func parseGitHubEvent(body []byte) (*domain.ExternalPREvent, error) {
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+
+	var action domain.ExternalAction
+	switch payload.Action {
+	case "opened":
+		action = domain.ExternalOpened
+	case "ready_for_review":
+		action = domain.ExternalReady
+	case "closed":
+		action = domain.ExternalClosed
+		if payload.PullRequest.Merged {
+			action = domain.ExternalMerged
+		}
+	case "reopened":
+		action = domain.ExternalReopened
+	default:
+		return nil, nil
+	}
+
+	event := &domain.ExternalPREvent{
+		Provider:    domain.ProviderGitHub,
+		Action:      action,
+		Repository:  payload.Repository.FullName,
+		Number:      payload.Number,
+		Title:       payload.PullRequest.Title,
+		Draft:       payload.PullRequest.Draft,
+		AuthorLogin: payload.PullRequest.User.Login,
+		ActorLogin:  payload.Sender.Login,
+	}
+
+	return event, validateExternalEvent(event)
+}
+
+// gitlabMergeRequestEvent is the part of GitLab's merge request webhook payload we use.
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"` // старое название draft
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// parseGitLabEvent maps a merge request payload onto an event.
+// GitLab doesn't send the author's username, so the user who opened
+// the merge request is taken as its author.
+// Returns nil event for actions that don't change the pull request status.
+func parseGitLabEvent(body []byte) (*domain.ExternalPREvent, error) {
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+
+	if payload.ObjectKind != "merge_request" {
+		return nil, nil
+	}
+
+	attributes := payload.ObjectAttributes
+
+	var action domain.ExternalAction
+	switch attributes.Action {
+	case "open":
+		action = domain.ExternalOpened
+	case "close":
+		action = domain.ExternalClosed
+	case "merge":
+		action = domain.ExternalMerged
+	case "reopen":
+		action = domain.ExternalReopened
+	case "update":
+		draft := payload.Changes.Draft
+		if draft == nil || !draft.Previous || draft.Current {
+			return nil, nil
+		}
+		action = domain.ExternalReady
+	default:
+		return nil, nil
+	}
+
+	event := &domain.ExternalPREvent{
+		Provider:    domain.ProviderGitLab,
+		Action:      action,
+		Repository:  payload.Project.PathWithNamespace,
+		Number:      attributes.IID,
+		Title:       attributes.Title,
+		Draft:       attributes.Draft || attributes.WorkInProgress,
+		AuthorLogin: payload.User.Username,
+		ActorLogin:  payload.User.Username,
+	}
+
+	return event, validateExternalEvent(event)
+}
+
+// validateExternalEvent checks fields every action relies on.
+func validateExternalEvent(event *domain.ExternalPREvent) error {
+	if event.Repository == "" || event.Number <= 0 {
+		return errors.New("repository and pull request number are required")
+	}
+
+	if event.Action == domain.ExternalOpened && (event.Title == "" || event.AuthorLogin == "") {
+		return errors.New("title and author are required for opened pull requests")
+	}
+
+	return nil
+}
+
+type AddIdentityRequest struct {
+	Provider domain.Provider `json:"provider"`
+	Login    string          `json:"login"`
+	UserID   string          `json:"user_id"`
+}
+
+type AddIdentityResponse struct {
+	Identity *domain.ExternalIdentity `json:"identity"`
+}
+
+func (h *IntegrationHandler) AddIdentity(w http.ResponseWriter, r *http.Request) {
+	var req AddIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	identity, err := h.integrationService.AddIdentity(r.Context(), &domain.ExternalIdentity{
+		Provider: req.Provider,
+		Login:    req.Login,
+		UserID:   req.UserID,
+	})
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := AddIdentityResponse{Identity: identity}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type ListIdentitiesResponse struct {
+	Identities []*domain.ExternalIdentity `json:"identities"`
+}
+
+func (h *IntegrationHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	provider := domain.Provider(r.URL.Query().Get("provider"))
+
+	identities, err := h.integrationService.ListIdentities(r.Context(), provider)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListIdentitiesResponse{Identities: identities}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RemoveIdentityRequest struct {
+	Provider domain.Provider `json:"provider"`
+	Login    string          `json:"login"`
+}
+
+func (h *IntegrationHandler) RemoveIdentity(w http.ResponseWriter, r *http.Request) {
+	var req RemoveIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Provider == "" || req.Login == "" {
+		h.logger.Warn("provider and login are required")
+		http.Error(w, "provider and login are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.integrationService.RemoveIdentity(r.Context(), req.Provider, req.Login); err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type ListFailedSyncsResponse struct {
+	Tasks []*domain.ReviewerSyncTask `json:"tasks"`
+}
+
+func (h *IntegrationHandler) ListFailedSyncs(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r)
+	if err != nil {
+		h.logger.Warn("invalid limit", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.reviewerSyncService.ListDeadLetters(r.Context(), limit)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListFailedSyncsResponse{Tasks: tasks}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RetrySyncRequest struct {
+	TaskID int64 `json:"task_id"`
+}
+
+func (h *IntegrationHandler) RetrySync(w http.ResponseWriter, r *http.Request) {
+	var req RetrySyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TaskID == 0 {
+		h.logger.Warn("task_id is required")
+		http.Error(w, "task_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.reviewerSyncService.RetryDeadLetter(r.Context(), req.TaskID); err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
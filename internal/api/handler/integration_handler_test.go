@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/pkg/webhook"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func TestParseGitHubEvent(t *testing.T) {
+	event := func(action domain.ExternalAction, draft bool, actor string) *domain.ExternalPREvent {
+		return &domain.ExternalPREvent{
+			Provider:    domain.ProviderGitHub,
+			Action:      action,
+			Repository:  "acme/backend",
+			Number:      42,
+			Title:       "Add reviewer sync",
+			Draft:       draft,
+			AuthorLogin: "octocat",
+			ActorLogin:  actor,
+		}
+	}
+
+	tests := []struct {
+		fixture string
+		want    *domain.ExternalPREvent
+	}{
+		{fixture: "github_opened.json", want: event(domain.ExternalOpened, true, "octocat")},
+		{fixture: "github_ready_for_review.json", want: event(domain.ExternalReady, false, "octocat")},
+		{fixture: "github_closed.json", want: event(domain.ExternalClosed, false, "hubot")},
+		{fixture: "github_closed_merged.json", want: event(domain.ExternalMerged, false, "hubot")},
+		{fixture: "github_reopened.json", want: event(domain.ExternalReopened, false, "hubot")},
+		{fixture: "github_labeled.json", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := parseGitHubEvent(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("parseGitHubEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGitHubEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGitHubEventInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{"action":`},
+		{name: "missing repository", body: `{"action":"closed","number":42}`},
+		{name: "opened without title", body: `{"action":"opened","number":42,"repository":{"full_name":"acme/backend"},"pull_request":{"user":{"login":"octocat"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseGitHubEvent([]byte(tt.body)); err == nil {
+				t.Error("parseGitHubEvent() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseGitLabEvent(t *testing.T) {
+	event := func(action domain.ExternalAction, draft bool, user string) *domain.ExternalPREvent {
+		return &domain.ExternalPREvent{
+			Provider:    domain.ProviderGitLab,
+			Action:      action,
+			Repository:  "acme/platform/backend",
+			Number:      7,
+			Title:       "Add reviewer sync",
+			Draft:       draft,
+			AuthorLogin: user,
+			ActorLogin:  user,
+		}
+	}
+
+	tests := []struct {
+		fixture string
+		want    *domain.ExternalPREvent
+	}{
+		{fixture: "gitlab_open.json", want: event(domain.ExternalOpened, true, "jdoe")},
+		{fixture: "gitlab_update_ready.json", want: event(domain.ExternalReady, false, "jdoe")},
+		{fixture: "gitlab_close.json", want: event(domain.ExternalClosed, false, "maintainer")},
+		{fixture: "gitlab_merge.json", want: event(domain.ExternalMerged, false, "maintainer")},
+		{fixture: "gitlab_reopen.json", want: event(domain.ExternalReopened, false, "maintainer")},
+		{fixture: "gitlab_update_title.json", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := parseGitLabEvent(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("parseGitLabEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGitLabEvent() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidGitLabToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		token    string
+		want     bool
+	}{
+		{name: "matching token", expected: "token", token: "token", want: true},
+		{name: "wrong token", expected: "token", token: "other", want: false},
+		{name: "token prefix", expected: "token", token: "tok", want: false},
+		{name: "missing token", expected: "token", token: "", want: false},
+		{name: "not configured", expected: "", token: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validGitLabToken(tt.expected, tt.token); got != tt.want {
+				t.Errorf("validGitLabToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The requests below are rejected or ignored before reaching the services.
+func newTestIntegrationHandler(t *testing.T) *IntegrationHandler {
+	t.Helper()
+
+	log, err := logger.New(&logger.Config{Level: "error", Format: "json"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	return NewIntegrationHandler(nil, nil, IntegrationConfig{GitHubSecret: "secret", GitLabToken: "token"}, log)
+}
+
+func TestGitHubSignature(t *testing.T) {
+	h := newTestIntegrationHandler(t)
+	body := readFixture(t, "github_opened.json")
+
+	tests := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{name: "valid signature", signature: webhook.Sign("secret", body), want: http.StatusOK},
+		{name: "wrong secret", signature: webhook.Sign("other", body), want: http.StatusUnauthorized},
+		{name: "missing signature", signature: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/github", bytes.NewReader(body))
+			req.Header.Set(githubSignatureHeader, tt.signature)
+			req.Header.Set(githubEventHeader, "ping")
+			rec := httptest.NewRecorder()
+
+			h.GitHub(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLabToken(t *testing.T) {
+	h := newTestIntegrationHandler(t)
+	body := readFixture(t, "gitlab_open.json")
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "valid token", token: "token", want: http.StatusOK},
+		{name: "wrong token", token: "other", want: http.StatusUnauthorized},
+		{name: "missing token", token: "", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/gitlab", bytes.NewReader(body))
+			req.Header.Set(gitlabTokenHeader, tt.token)
+			req.Header.Set(gitlabEventHeader, "Push Hook")
+			rec := httptest.NewRecorder()
+
+			h.GitLab(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
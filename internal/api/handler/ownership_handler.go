@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/service"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+)
+
+type OwnershipHandler struct {
+	ownershipService *service.OwnershipService
+	logger           *logger.Logger
+}
+
+func NewOwnershipHandler(ownershipService *service.OwnershipService, logger *logger.Logger) *OwnershipHandler {
+	return &OwnershipHandler{
+		ownershipService: ownershipService,
+		logger:           logger.Component("handler/ownership"),
+	}
+}
+
+func (h *OwnershipHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/health", healthCheck)
+	r.Post("/add", h.AddRule)
+	r.Get("/list", h.ListRules)
+	r.Post("/remove", h.RemoveRule)
+
+	return r
+}
+
+type AddRuleRequest struct {
+	TeamName string   `json:"team_name"`
+	Pattern  string   `json:"pattern"`
+	Owners   []string `json:"owners"`
+}
+
+type AddRuleResponse struct {
+	Rule *domain.OwnershipRule `json:"rule"`
+}
+
+func (h *OwnershipHandler) AddRule(w http.ResponseWriter, r *http.Request) {
+	var req AddRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.ownershipService.AddRule(r.Context(), &domain.OwnershipRule{
+		TeamName: req.TeamName,
+		Pattern:  req.Pattern,
+		Owners:   req.Owners,
+	})
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := AddRuleResponse{Rule: rule}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type ListRulesResponse struct {
+	TeamName string                  `json:"team_name"`
+	Rules    []*domain.OwnershipRule `json:"rules"`
+}
+
+func (h *OwnershipHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.logger.Warn("team_name query parameter is required")
+		http.Error(w, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.ownershipService.ListRules(r.Context(), teamName)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListRulesResponse{
+		TeamName: teamName,
+		Rules:    rules,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RemoveRuleRequest struct {
+	RuleID int64 `json:"rule_id"`
+}
+
+func (h *OwnershipHandler) RemoveRule(w http.ResponseWriter, r *http.Request) {
+	var req RemoveRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.RuleID == 0 {
+		h.logger.Warn("rule_id is required")
+		http.Error(w, "rule_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ownershipService.RemoveRule(r.Context(), req.RuleID); err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,11 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/ZertGraf/avito-test/internal/domain"
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
 	"github.com/ZertGraf/avito-test/internal/service"
 	"github.com/go-chi/chi/v5"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type PRHandler struct {
@@ -26,20 +29,28 @@ func (h *PRHandler) Routes() http.Handler {
 
 	r.Get("/health", healthCheck)
 	r.Post("/create", h.CreatePR)
+	r.Get("/get", h.GetPR)
 	r.Post("/merge", h.MergePR)
 	r.Post("/reassign", h.ReassignReviewer)
+	r.Post("/review", h.SubmitReview)
+	r.Post("/ready", h.MarkReady)
+	r.Post("/close", h.ClosePR)
+	r.Post("/reopen", h.ReopenPR)
+	r.Get("/history", h.GetHistory)
+	r.Get("/overdue", h.GetOverdue)
+	r.Post("/import", h.ImportPRs)
+	r.Get("/export", h.ExportPRs)
+	r.Get("/list", h.ListPRs)
 
 	return r
 }
 
 type CreatePRRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-}
-
-type CreatePRResponse struct {
-	PR *domain.PullRequest `json:"pr"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Files           []string `json:"files,omitempty"` // изменённые пути для поиска code owners
+	Draft           bool     `json:"draft,omitempty"`
 }
 
 func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
@@ -56,7 +67,13 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	response, err := h.prService.CreatePR(r.Context(), service.CreatePRInput{
+		PullRequestID:   req.PullRequestID,
+		PullRequestName: req.PullRequestName,
+		AuthorID:        req.AuthorID,
+		Files:           req.Files,
+		Draft:           req.Draft,
+	})
 	if err != nil {
 		WriteError(w, err, h.logger)
 		return
@@ -65,7 +82,6 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 
-	response := CreatePRResponse{PR: pr}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Error("failed to encode response", "error", err)
 	}
@@ -73,6 +89,7 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	ActorID       string `json:"actor_id,omitempty"`
 }
 
 type MergePRResponse struct {
@@ -93,7 +110,7 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID)
+	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID, req.ActorID)
 	if err != nil {
 		WriteError(w, err, h.logger)
 		return
@@ -108,9 +125,38 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type GetPRResponse struct {
+	PR *domain.PullRequest `json:"pr"`
+}
+
+func (h *PRHandler) GetPR(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.logger.Warn("pull_request_id query parameter is required")
+		http.Error(w, "pull_request_id is required", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.prService.GetPR(r.Context(), prID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := GetPRResponse{PR: pr}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
 type ReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	ActorID       string `json:"actor_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
 }
 
 type ReassignResponse struct {
@@ -132,7 +178,7 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, newReviewerID, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	pr, newReviewerID, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.ActorID, req.Reason)
 	if err != nil {
 		WriteError(w, err, h.logger)
 		return
@@ -150,3 +196,264 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("failed to encode response", "error", err)
 	}
 }
+
+type ReviewRequest struct {
+	PullRequestID string                `json:"pull_request_id"`
+	ReviewerID    string                `json:"reviewer_id"`
+	Decision      domain.ReviewDecision `json:"decision"`
+}
+
+type ReviewResponse struct {
+	PR *domain.PullRequest `json:"pr"`
+}
+
+func (h *PRHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.PullRequestID == "" || req.ReviewerID == "" || req.Decision == "" {
+		h.logger.Warn("missing required fields")
+		http.Error(w, "pull_request_id, reviewer_id and decision are required", http.StatusBadRequest)
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(r.Context(), req.PullRequestID, req.ReviewerID, req.Decision)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ReviewResponse{PR: pr}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type TransitionRequest struct {
+	PullRequestID string   `json:"pull_request_id"`
+	ActorID       string   `json:"actor_id,omitempty"`
+	Files         []string `json:"files,omitempty"` // только для /ready
+}
+
+type TransitionResponse struct {
+	PR *domain.PullRequest `json:"pr"`
+}
+
+// decodeTransition decodes and validates a status transition request.
+// Writes an error response and returns false on failure.
+func (h *PRHandler) decodeTransition(w http.ResponseWriter, r *http.Request) (TransitionRequest, bool) {
+	var req TransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return req, false
+	}
+
+	if req.PullRequestID == "" {
+		h.logger.Warn("pull_request_id is required")
+		http.Error(w, "pull_request_id is required", http.StatusBadRequest)
+		return req, false
+	}
+
+	return req, true
+}
+
+func (h *PRHandler) MarkReady(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeTransition(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.prService.MarkReady(r.Context(), req.PullRequestID, req.ActorID, req.Files)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+func (h *PRHandler) ClosePR(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeTransition(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.ClosePR(r.Context(), req.PullRequestID, req.ActorID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := TransitionResponse{PR: pr}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+func (h *PRHandler) ReopenPR(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeTransition(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.prService.ReopenPR(r.Context(), req.PullRequestID, req.ActorID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type HistoryResponse struct {
+	PullRequestID string                  `json:"pull_request_id"`
+	Events        []*domain.ReviewerEvent `json:"events"`
+}
+
+func (h *PRHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.logger.Warn("pull_request_id query parameter is required")
+		http.Error(w, "pull_request_id is required", http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.prService.GetHistory(r.Context(), prID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := HistoryResponse{
+		PullRequestID: prID,
+		Events:        events,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type OverdueResponse struct {
+	Overdue []*domain.OverdueReview `json:"overdue"`
+}
+
+// GetOverdue lists reviews past SLA, optionally filtered by ?team_name=X.
+func (h *PRHandler) GetOverdue(w http.ResponseWriter, r *http.Request) {
+	overdue, err := h.prService.GetOverdue(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := OverdueResponse{Overdue: overdue}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// ListPRs serves a page of pull requests, newest first. Supports filters
+// status, author_id, reviewer_id, team_name, q (name substring) and
+// created_from/created_to, merged_from/merged_to; paging with limit and cursor.
+func (h *PRHandler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePRFilter(r)
+	if err != nil {
+		h.logger.Warn("invalid pr filter", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		h.logger.Warn("invalid limit", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.prService.ListPRs(r.Context(), filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// parsePRFilter reads pull request filters from query parameters.
+func parsePRFilter(r *http.Request) (domain.PRFilter, error) {
+	query := r.URL.Query()
+	filter := domain.PRFilter{
+		Status:     domain.PRStatus(query.Get("status")),
+		TeamName:   query.Get("team_name"),
+		AuthorID:   query.Get("author_id"),
+		ReviewerID: query.Get("reviewer_id"),
+		Query:      query.Get("q"),
+	}
+
+	bounds := []struct {
+		name   string
+		target **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+
+	for _, bound := range bounds {
+		value, err := parseTimeParam(r, bound.name)
+		if err != nil {
+			return domain.PRFilter{}, err
+		}
+		*bound.target = value
+	}
+
+	return filter, nil
+}
+
+// parseLimit reads an optional ?limit= page size; zero means default.
+func parseLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/service"
+	"io"
+	"net/http"
+)
+
+// maxImportBodyBytes limits the size of an import request.
+const maxImportBodyBytes = 32 << 20
+
+// maxImportLineBytes limits the size of a single NDJSON line.
+const maxImportLineBytes = 1 << 20
+
+// exportFlushEvery is the number of exported pull requests between flushes.
+const exportFlushEvery = 100
+
+type ImportErrorResponse struct {
+	Line          int       `json:"line"`
+	PullRequestID string    `json:"pull_request_id,omitempty"`
+	Code          ErrorCode `json:"code"`
+	Message       string    `json:"message"`
+}
+
+type ImportResponse struct {
+	Imported   int                   `json:"imported"`
+	Failed     int                   `json:"failed"`
+	RolledBack bool                  `json:"rolled_back"`
+	Errors     []ImportErrorResponse `json:"errors"`
+}
+
+// ImportPRs imports pull requests from a JSON array or NDJSON body.
+// ?atomic=true imports all entries in one transaction or none of them.
+func (h *PRHandler) ImportPRs(w http.ResponseWriter, r *http.Request) {
+	atomic := r.URL.Query().Get("atomic") == "true"
+
+	entries, err := decodeImport(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		h.logger.Warn("invalid import body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(entries) == 0 {
+		h.logger.Warn("empty import")
+		http.Error(w, "no pull requests to import", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.prService.ImportPRs(r.Context(), entries, atomic, r.URL.Query().Get("actor_id"))
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	response := ImportResponse{
+		Imported:   result.Imported,
+		Failed:     result.Failed,
+		RolledBack: result.RolledBack,
+		Errors:     make([]ImportErrorResponse, 0, len(result.Errors)),
+	}
+
+	for _, importErr := range result.Errors {
+		_, detail := mapError(importErr.Err)
+		response.Errors = append(response.Errors, ImportErrorResponse{
+			Line:          importErr.Line,
+			PullRequestID: importErr.PullRequestID,
+			Code:          detail.Error.Code,
+			Message:       detail.Error.Message,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// decodeImport reads pull requests from a JSON array or NDJSON.
+// Entries that aren't valid pull requests are returned with Err set;
+// malformed JSON of the whole body fails the import.
+func decodeImport(body io.Reader) ([]service.ImportEntry, error) {
+	reader := bufio.NewReader(body)
+
+	// Skip leading whitespace to tell an array from NDJSON
+	for {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read body: %w", err)
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			if err := reader.UnreadByte(); err != nil {
+				return nil, fmt.Errorf("read body: %w", err)
+			}
+			if b == '[' {
+				return decodeImportArray(reader)
+			}
+			return decodeImportLines(reader)
+		}
+	}
+}
+
+func decodeImportArray(body io.Reader) ([]service.ImportEntry, error) {
+	decoder := json.NewDecoder(body)
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid json array: %w", err)
+	}
+
+	var entries []service.ImportEntry
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid json array element %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, parseImportEntry(len(entries)+1, raw))
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid json array: %w", err)
+	}
+
+	return entries, nil
+}
+
+func decodeImportLines(body io.Reader) ([]service.ImportEntry, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineBytes)
+
+	var entries []service.ImportEntry
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		entries = append(entries, parseImportEntry(line, raw))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson: %w", err)
+	}
+
+	return entries, nil
+}
+
+func parseImportEntry(line int, raw []byte) service.ImportEntry {
+	var pr domain.PullRequest
+	if err := json.Unmarshal(raw, &pr); err != nil {
+		return service.ImportEntry{
+			Line: line,
+			Err:  fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err),
+		}
+	}
+	return service.ImportEntry{Line: line, PR: &pr}
+}
+
+// ExportPRs streams pull requests as NDJSON in the format accepted by ImportPRs.
+// Takes the same filters as ListPRs.
+func (h *PRHandler) ExportPRs(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePRFilter(r)
+	if err != nil {
+		h.logger.Warn("invalid pr filter", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	started := false
+	exported := 0
+
+	err = h.prService.ExportPRs(r.Context(), filter, func(pr *domain.PullRequest) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if err := encoder.Encode(pr); err != nil {
+			return err
+		}
+
+		exported++
+		if flusher != nil && exported%exportFlushEvery == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err != nil && !started:
+		WriteError(w, err, h.logger)
+	case err != nil:
+		// Status is already sent, the client sees a truncated stream
+		h.logger.Error("export interrupted", "error", err)
+	case !started:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
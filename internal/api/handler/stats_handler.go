@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/service"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type StatsHandler struct {
+	statsService *service.StatsService
+	logger       *logger.Logger
+}
+
+func NewStatsHandler(statsService *service.StatsService, logger *logger.Logger) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+		logger:       logger.Component("handler/stats"),
+	}
+}
+
+func (h *StatsHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/health", healthCheck)
+	r.Get("/users", h.UserStats)
+	r.Get("/teams", h.TeamStats)
+	r.Get("/cycle-time", h.CycleTime)
+
+	return r
+}
+
+type UserStatsResponse struct {
+	Window domain.StatsWindow  `json:"window"`
+	Users  []*domain.UserStats `json:"users"`
+}
+
+func (h *StatsHandler) UserStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseWindow(r)
+	if err != nil {
+		h.logger.Warn("invalid stats window", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.statsService.UserStats(r.Context(), r.URL.Query().Get("team_name"), window)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := UserStatsResponse{
+		Window: window,
+		Users:  stats,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type TeamStatsResponse struct {
+	Window domain.StatsWindow  `json:"window"`
+	Teams  []*domain.TeamStats `json:"teams"`
+}
+
+func (h *StatsHandler) TeamStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseWindow(r)
+	if err != nil {
+		h.logger.Warn("invalid stats window", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.statsService.TeamStats(r.Context(), window)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := TeamStatsResponse{
+		Window: window,
+		Teams:  stats,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type CycleTimeResponse struct {
+	GroupBy domain.CycleTimeGroup    `json:"group_by"`
+	Window  domain.StatsWindow       `json:"window"`
+	Groups  []*domain.CycleTimeStats `json:"groups"`
+}
+
+// CycleTime serves turnaround percentiles as JSON, or as CSV with ?format=csv.
+// Grouping is chosen with ?group_by=team|author|reviewer (team by default).
+func (h *StatsHandler) CycleTime(w http.ResponseWriter, r *http.Request) {
+	window, err := parseWindow(r)
+	if err != nil {
+		h.logger.Warn("invalid stats window", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := domain.CycleTimeGroup(r.URL.Query().Get("group_by"))
+	if groupBy == "" {
+		groupBy = domain.CycleTimeByTeam
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.logger.Warn("unknown format", "format", format)
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.statsService.CycleTime(r.Context(), groupBy, window)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	if format == "csv" {
+		h.writeCycleTimeCSV(w, groupBy, stats)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := CycleTimeResponse{
+		GroupBy: groupBy,
+		Window:  window,
+		Groups:  stats,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// writeCycleTimeCSV writes cycle-time metrics as a CSV attachment.
+// Durations are in seconds; missing percentiles are left empty.
+func (h *StatsHandler) writeCycleTimeCSV(w http.ResponseWriter, groupBy domain.CycleTimeGroup, stats []*domain.CycleTimeStats) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cycle-time-%s.csv"`, groupBy))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+
+	records := [][]string{{
+		string(groupBy),
+		"prs", "cycle_time_p50", "cycle_time_p90", "cycle_time_p99",
+		"reviews", "review_time_p50", "review_time_p90", "review_time_p99",
+	}}
+
+	for _, s := range stats {
+		records = append(records, []string{
+			s.Group,
+			strconv.Itoa(s.PRs), formatSeconds(s.CycleTime.P50), formatSeconds(s.CycleTime.P90), formatSeconds(s.CycleTime.P99),
+			strconv.Itoa(s.Reviews), formatSeconds(s.ReviewTime.P50), formatSeconds(s.ReviewTime.P90), formatSeconds(s.ReviewTime.P99),
+		})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		h.logger.Error("failed to write csv", "error", err)
+	}
+}
+
+// formatSeconds renders an optional duration in seconds for CSV output.
+func formatSeconds(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 0, 64)
+}
+
+// parseWindow reads optional ?from= and ?to= RFC 3339 timestamps.
+func parseWindow(r *http.Request) (domain.StatsWindow, error) {
+	from, err := parseTimeParam(r, "from")
+	if err != nil {
+		return domain.StatsWindow{}, err
+	}
+
+	to, err := parseTimeParam(r, "to")
+	if err != nil {
+		return domain.StatsWindow{}, err
+	}
+
+	return domain.StatsWindow{From: from, To: to}, nil
+}
+
+// parseTimeParam reads an optional RFC 3339 timestamp query parameter.
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+	}
+
+	return &parsed, nil
+}
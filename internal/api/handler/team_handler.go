@@ -7,17 +7,20 @@ import (
 	"github.com/ZertGraf/avito-test/internal/service"
 	"github.com/go-chi/chi/v5"
 	"net/http"
+	"strconv"
 )
 
 type TeamHandler struct {
-	teamService *service.TeamService
-	logger      *logger.Logger
+	teamService     *service.TeamService
+	fairnessService *service.FairnessService
+	logger          *logger.Logger
 }
 
-func NewTeamHandler(teamService *service.TeamService, logger *logger.Logger) *TeamHandler {
+func NewTeamHandler(teamService *service.TeamService, fairnessService *service.FairnessService, logger *logger.Logger) *TeamHandler {
 	return &TeamHandler{
-		teamService: teamService,
-		logger:      logger,
+		teamService:     teamService,
+		fairnessService: fairnessService,
+		logger:          logger,
 	}
 }
 
@@ -29,11 +32,18 @@ func (h *TeamHandler) Routes() http.Handler {
 
 	r.Post("/add", h.CreateTeam)
 	r.Get("/get", h.GetTeam)
+	r.Post("/updateSettings", h.UpdateSettings)
+	r.Post("/members/add", h.AddMember)
+	r.Post("/members/remove", h.RemoveMember)
+	r.Post("/delete", h.DeleteTeam)
+	r.Get("/fairness", h.Fairness)
+	r.Post("/rebalance", h.Rebalance)
 	return r
 }
 
 func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
-	var team domain.Team
+	// Settings omitted from the request keep their defaults
+	team := domain.Team{TeamSettings: domain.DefaultTeamSettings()}
 	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
 		h.logger.Warn("invalid request body", "error", err)
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -78,6 +88,206 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type UpdateSettingsRequest struct {
+	TeamName string `json:"team_name"`
+	domain.TeamSettingsUpdate
+}
+
+func (h *TeamHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TeamName == "" {
+		h.logger.Warn("team_name is required")
+		http.Error(w, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.UpdateSettings(r.Context(), req.TeamName, req.TeamSettingsUpdate)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(team); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type AddMemberRequest struct {
+	TeamName string `json:"team_name"`
+	domain.TeamMember
+}
+
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	// New members are active unless stated otherwise
+	req := AddMemberRequest{TeamMember: domain.TeamMember{IsActive: true}}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TeamName == "" {
+		h.logger.Warn("team_name is required")
+		http.Error(w, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamService.AddMember(r.Context(), req.TeamName, req.TeamMember)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(team); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RemoveMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	var req RemoveMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		h.logger.Warn("team_name and user_id are required")
+		http.Error(w, "team_name and user_id are required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.teamService.RemoveMember(r.Context(), req.TeamName, req.UserID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var req DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TeamName == "" {
+		h.logger.Warn("team_name is required")
+		http.Error(w, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(r.Context(), req.TeamName); err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Fairness reports review load distribution of a team and proposes rebalancing moves.
+// Optional ?threshold= sets the outlier threshold in standard deviations.
+func (h *TeamHandler) Fairness(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		h.logger.Warn("team_name query parameter is required")
+		http.Error(w, "team_name is required", http.StatusBadRequest)
+		return
+	}
+
+	window, err := parseWindow(r)
+	if err != nil {
+		h.logger.Warn("invalid stats window", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	threshold := 0.0
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		threshold, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			h.logger.Warn("invalid threshold", "threshold", value)
+			http.Error(w, "threshold must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.fairnessService.Report(r.Context(), teamName, window, threshold)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RebalanceRequest struct {
+	Moves   []domain.RebalanceMove `json:"moves"`
+	ActorID string                 `json:"actor_id,omitempty"`
+}
+
+type RebalanceResponse struct {
+	Reassignments []domain.Reassignment `json:"reassignments"`
+}
+
+// Rebalance applies moves confirmed from a fairness report.
+func (h *TeamHandler) Rebalance(w http.ResponseWriter, r *http.Request) {
+	var req RebalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reassignments, err := h.fairnessService.ApplyRebalance(r.Context(), req.Moves, req.ActorID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := RebalanceResponse{Reassignments: reassignments}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
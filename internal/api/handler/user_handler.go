@@ -10,20 +10,23 @@ import (
 )
 
 type UserHandler struct {
-	userService *service.UserService
-	prService   *service.PRService
-	logger      *logger.Logger
+	userService         *service.UserService
+	prService           *service.PRService
+	availabilityService *service.AvailabilityService
+	logger              *logger.Logger
 }
 
 func NewUserHandler(
 	userService *service.UserService,
 	prService *service.PRService,
+	availabilityService *service.AvailabilityService,
 	logger *logger.Logger,
 ) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		prService:   prService,
-		logger:      logger.Component("handler/user"),
+		userService:         userService,
+		prService:           prService,
+		availabilityService: availabilityService,
+		logger:              logger.Component("handler/user"),
 	}
 }
 
@@ -31,19 +34,50 @@ func (h *UserHandler) Routes() http.Handler {
 	r := chi.NewRouter()
 
 	r.Get("/health", healthCheck)
+	r.Get("/get", h.GetUser)
 	r.Post("/setIsActive", h.SetIsActive)
+	r.Post("/bulkDeactivate", h.BulkDeactivate)
+	r.Post("/setReviewCap", h.SetReviewCap)
+	r.Post("/setWorkingHours", h.SetWorkingHours)
+	r.Post("/moveTeam", h.MoveTeam)
+	r.Post("/availability", h.AddUnavailability)
+	r.Get("/availability", h.ListUnavailability)
 	r.Get("/getReview", h.GetReview)
 
 	return r
 }
 
-type SetIsActiveRequest struct {
-	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+type GetUserResponse struct {
+	User *domain.UserProfile `json:"user"`
 }
 
-type SetIsActiveResponse struct {
-	User *domain.User `json:"user"`
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		h.logger.Warn("user_id query parameter is required")
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := GetUserResponse{User: profile}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type SetIsActiveRequest struct {
+	UserID          string `json:"user_id"`
+	IsActive        bool   `json:"is_active"`
+	ReassignReviews bool   `json:"reassign_reviews"`
 }
 
 func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
@@ -54,7 +88,7 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.SetIsActive(r.Context(), req.UserID, req.IsActive) // ← вот тут
+	response, err := h.userService.SetIsActive(r.Context(), req.UserID, req.IsActive, req.ReassignReviews)
 	if err != nil {
 		WriteError(w, err, h.logger)
 		return
@@ -63,7 +97,145 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	response := SetIsActiveResponse{User: user}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type SetWorkingHoursRequest struct {
+	UserID    string `json:"user_id"`
+	Timezone  string `json:"timezone"`
+	WorkStart string `json:"work_start"`
+	WorkEnd   string `json:"work_end"`
+}
+
+type SetWorkingHoursResponse struct {
+	User *domain.User `json:"user"`
+}
+
+func (h *UserHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var req SetWorkingHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" {
+		h.logger.Warn("user_id is required")
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.SetWorkingHours(r.Context(), req.UserID, req.Timezone, req.WorkStart, req.WorkEnd)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := SetWorkingHoursResponse{User: user}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type MoveTeamRequest struct {
+	UserID          string `json:"user_id"`
+	TeamName        string `json:"team_name"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+}
+
+func (h *UserHandler) MoveTeam(w http.ResponseWriter, r *http.Request) {
+	var req MoveTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" || req.TeamName == "" {
+		h.logger.Warn("user_id and team_name are required")
+		http.Error(w, "user_id and team_name are required", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.userService.MoveTeam(r.Context(), req.UserID, req.TeamName, req.ReassignReviews)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type BulkDeactivateRequest struct {
+	TeamName string   `json:"team_name,omitempty"`
+	UserIDs  []string `json:"user_ids,omitempty"`
+}
+
+func (h *UserHandler) BulkDeactivate(w http.ResponseWriter, r *http.Request) {
+	var req BulkDeactivateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.userService.BulkDeactivate(r.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type SetReviewCapRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type SetReviewCapResponse struct {
+	User *domain.User `json:"user"`
+}
+
+func (h *UserHandler) SetReviewCap(w http.ResponseWriter, r *http.Request) {
+	var req SetReviewCapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" {
+		h.logger.Warn("user_id is required")
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := SetReviewCapResponse{User: user}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.logger.Error("failed to encode response", "error", err)
 	}
@@ -72,8 +244,11 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 type GetReviewResponse struct {
 	UserID       string                     `json:"user_id"`
 	PullRequests []*domain.PullRequestShort `json:"pull_requests"`
+	NextCursor   string                     `json:"next_cursor,omitempty"`
 }
 
+// GetReview lists pull requests the user reviews, newest first.
+// ?status= defaults to OPEN; ?limit= and ?cursor= page through the list.
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
@@ -82,8 +257,17 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit, err := parseLimit(r)
+	if err != nil {
+		h.logger.Warn("invalid limit", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := domain.PRStatus(r.URL.Query().Get("status"))
+
 	// вызываем PRService напрямую
-	prs, err := h.prService.GetReviewsByUser(r.Context(), userID)
+	page, err := h.prService.GetReviewsByUser(r.Context(), userID, status, r.URL.Query().Get("cursor"), limit)
 	if err != nil {
 		WriteError(w, err, h.logger)
 		return
@@ -94,7 +278,72 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 
 	response := GetReviewResponse{
 		UserID:       userID,
-		PullRequests: prs,
+		PullRequests: page.PullRequests,
+		NextCursor:   page.NextCursor,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type AddUnavailabilityResponse struct {
+	Unavailability *domain.Unavailability `json:"unavailability"`
+}
+
+func (h *UserHandler) AddUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req domain.Unavailability
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	period, err := h.availabilityService.AddUnavailability(r.Context(), &domain.Unavailability{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := AddUnavailabilityResponse{Unavailability: period}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type ListUnavailabilityResponse struct {
+	UserID         string                   `json:"user_id"`
+	Unavailability []*domain.Unavailability `json:"unavailability"`
+}
+
+func (h *UserHandler) ListUnavailability(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		h.logger.Warn("user_id query parameter is required")
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	periods, err := h.availabilityService.ListUnavailability(r.Context(), userID)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListUnavailabilityResponse{
+		UserID:         userID,
+		Unavailability: periods,
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/service"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strconv"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+	logger         *logger.Logger
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService, logger *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger.Component("handler/webhook"),
+	}
+}
+
+func (h *WebhookHandler) Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/health", healthCheck)
+	r.Post("/add", h.AddSubscription)
+	r.Get("/list", h.ListSubscriptions)
+	r.Post("/remove", h.RemoveSubscription)
+	r.Get("/deliveries", h.ListDeliveries)
+
+	return r
+}
+
+type AddSubscriptionRequest struct {
+	URL        string             `json:"url"`
+	Secret     string             `json:"secret"`
+	EventTypes []domain.EventType `json:"event_types"`
+}
+
+type AddSubscriptionResponse struct {
+	Subscription *domain.WebhookSubscription `json:"subscription"`
+}
+
+func (h *WebhookHandler) AddSubscription(w http.ResponseWriter, r *http.Request) {
+	var req AddSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.webhookService.AddSubscription(r.Context(), &domain.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	response := AddSubscriptionResponse{Subscription: subscription}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type ListSubscriptionsResponse struct {
+	Subscriptions []*domain.WebhookSubscription `json:"subscriptions"`
+}
+
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListSubscriptionsResponse{Subscriptions: subscriptions}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+type RemoveSubscriptionRequest struct {
+	SubscriptionID int64 `json:"subscription_id"`
+}
+
+func (h *WebhookHandler) RemoveSubscription(w http.ResponseWriter, r *http.Request) {
+	var req RemoveSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("invalid request body", "error", err)
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.SubscriptionID == 0 {
+		h.logger.Warn("subscription_id is required")
+		http.Error(w, "subscription_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.webhookService.RemoveSubscription(r.Context(), req.SubscriptionID); err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type ListDeliveriesResponse struct {
+	SubscriptionID int64                     `json:"subscription_id"`
+	Deliveries     []*domain.WebhookDelivery `json:"deliveries"`
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := strconv.ParseInt(r.URL.Query().Get("subscription_id"), 10, 64)
+	if err != nil || subscriptionID == 0 {
+		h.logger.Warn("subscription_id query parameter is required")
+		http.Error(w, "subscription_id is required", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		h.logger.Warn("invalid limit", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status := domain.DeliveryStatus(r.URL.Query().Get("status"))
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), subscriptionID, status, limit)
+	if err != nil {
+		WriteError(w, err, h.logger)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response := ListDeliveriesResponse{
+		SubscriptionID: subscriptionID,
+		Deliveries:     deliveries,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
@@ -30,9 +30,13 @@ func NewHTTPServer(config *ServerConfig,
 	teamHandler *handler.TeamHandler,
 	userHandler *handler.UserHandler,
 	prHandler *handler.PRHandler,
+	ownershipHandler *handler.OwnershipHandler,
+	statsHandler *handler.StatsHandler,
+	webhookHandler *handler.WebhookHandler,
+	integrationHandler *handler.IntegrationHandler,
 	logger *logger.Logger) *HTTPServer {
 
-	router := setupRouter(teamHandler, userHandler, prHandler, logger)
+	router := setupRouter(teamHandler, userHandler, prHandler, ownershipHandler, statsHandler, webhookHandler, integrationHandler, logger)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", config.Host, config.Port),
@@ -80,6 +84,10 @@ func setupRouter(
 	teamHandler *handler.TeamHandler,
 	userHandler *handler.UserHandler,
 	prHandler *handler.PRHandler,
+	ownershipHandler *handler.OwnershipHandler,
+	statsHandler *handler.StatsHandler,
+	webhookHandler *handler.WebhookHandler,
+	integrationHandler *handler.IntegrationHandler,
 	logger *logger.Logger,
 ) http.Handler {
 	r := chi.NewRouter()
@@ -101,6 +109,10 @@ func setupRouter(
 	r.Mount("/team", teamHandler.Routes())
 	r.Mount("/pullRequest", prHandler.Routes())
 	r.Mount("/users", userHandler.Routes())
+	r.Mount("/owners", ownershipHandler.Routes())
+	r.Mount("/stats", statsHandler.Routes())
+	r.Mount("/webhooks", webhookHandler.Routes())
+	r.Mount("/integrations", integrationHandler.Routes())
 
 	return r
 }
@@ -2,14 +2,20 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ZertGraf/avito-test/internal/api"
 	"github.com/ZertGraf/avito-test/internal/api/handler"
+	"github.com/ZertGraf/avito-test/internal/domain"
 	"github.com/ZertGraf/avito-test/internal/pkg/config"
+	"github.com/ZertGraf/avito-test/internal/pkg/github"
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
 	"github.com/ZertGraf/avito-test/internal/pkg/postgres"
+	"github.com/ZertGraf/avito-test/internal/pkg/webhook"
+	"github.com/ZertGraf/avito-test/internal/pkg/worker"
 	"github.com/ZertGraf/avito-test/internal/repository"
 	"github.com/ZertGraf/avito-test/internal/service"
+	"time"
 )
 
 type Application struct {
@@ -18,19 +24,44 @@ type Application struct {
 	Postgres *postgres.Connection
 	Migrator *postgres.Migrator
 
-	TeamRepo repository.TeamRepository
-	UserRepo repository.UserRepository
-	PRRepo   repository.PRRepository
+	TeamRepo         repository.TeamRepository
+	UserRepo         repository.UserRepository
+	PRRepo           repository.PRRepository
+	OwnershipRepo    repository.OwnershipRepository
+	AvailabilityRepo repository.AvailabilityRepository
+	StatsRepo        repository.StatsRepository
+	WebhookRepo      repository.WebhookRepository
+	OutboxRepo       repository.OutboxRepository
+	IdentityRepo     repository.IdentityRepository
+	ReviewerSyncRepo repository.ReviewerSyncRepository
+	Tx               repository.Transactor
 
-	TeamService *service.TeamService
-	UserService *service.UserService
-	PRService   *service.PRService
+	TeamService         *service.TeamService
+	UserService         *service.UserService
+	PRService           *service.PRService
+	OwnershipService    *service.OwnershipService
+	AvailabilityService *service.AvailabilityService
+	StatsService        *service.StatsService
+	FairnessService     *service.FairnessService
+	WebhookService      *service.WebhookService
+	OutboxService       *service.OutboxService
+	IntegrationService  *service.IntegrationService
+	ReviewerSyncService *service.ReviewerSyncService
 
-	TeamHandler *handler.TeamHandler
-	UserHandler *handler.UserHandler
-	PRHandler   *handler.PRHandler
+	TeamHandler        *handler.TeamHandler
+	UserHandler        *handler.UserHandler
+	PRHandler          *handler.PRHandler
+	OwnershipHandler   *handler.OwnershipHandler
+	StatsHandler       *handler.StatsHandler
+	WebhookHandler     *handler.WebhookHandler
+	IntegrationHandler *handler.IntegrationHandler
 
-	HTTPServer *api.HTTPServer
+	HTTPServer    *api.HTTPServer
+	AbsenceWorker *worker.Periodic
+	SLAWorker     *worker.Periodic
+	WebhookWorker *worker.Periodic
+	OutboxWorker  *worker.Periodic
+	SyncWorker    *worker.Periodic
 }
 
 func New() (*Application, error) {
@@ -95,14 +126,65 @@ func (app *Application) Init(ctx context.Context) error {
 	app.TeamRepo = repository.NewTeamRepo(app.Postgres.Pool(), app.Logger)
 	app.UserRepo = repository.NewUserRepo(app.Postgres.Pool(), app.Logger)
 	app.PRRepo = repository.NewPRRepo(app.Postgres.Pool(), app.Logger)
+	app.OwnershipRepo = repository.NewOwnershipRepo(app.Postgres.Pool(), app.Logger)
+	app.AvailabilityRepo = repository.NewAvailabilityRepo(app.Postgres.Pool(), app.Logger)
+	app.StatsRepo = repository.NewStatsRepo(app.Postgres.Pool(), app.Logger)
+	app.WebhookRepo = repository.NewWebhookRepo(app.Postgres.Pool(), app.Logger)
+	app.OutboxRepo = repository.NewOutboxRepo(app.Postgres.Pool(), app.Logger)
+	app.IdentityRepo = repository.NewIdentityRepo(app.Postgres.Pool(), app.Logger)
+	app.ReviewerSyncRepo = repository.NewReviewerSyncRepo(app.Postgres.Pool(), app.Logger)
+	app.Tx = repository.NewTxManager(app.Postgres.Pool(), app.Logger)
 
-	app.TeamService = service.NewTeamService(app.TeamRepo, app.Logger)
-	app.UserService = service.NewUserService(app.UserRepo, app.Logger)
-	app.PRService = service.NewPRService(app.PRRepo, app.UserRepo, app.Logger)
+	// a batch is sent sequentially, so a claim must outlive the worst case with a margin
+	webhookLease := 2 * app.Config.WebhookTimeout * time.Duration(app.Config.WebhookBatchSize)
+	app.WebhookService = service.NewWebhookService(app.WebhookRepo, webhook.NewSender(app.Config.WebhookTimeout), service.WebhookConfig{
+		BatchSize:   app.Config.WebhookBatchSize,
+		MaxAttempts: app.Config.WebhookMaxAttempts,
+		RetryBase:   app.Config.WebhookRetryBase,
+		RetryMax:    app.Config.WebhookRetryMax,
+		Lease:       webhookLease,
+	}, app.Logger)
+
+	codeHosts := map[domain.Provider]service.CodeHostClient{}
+	if app.Config.GitHubToken != "" {
+		codeHosts[domain.ProviderGitHub] = github.NewClient(app.Config.GitHubAPIURL, app.Config.GitHubToken, app.Config.GitHubTimeout)
+	}
 
-	app.TeamHandler = handler.NewTeamHandler(app.TeamService, app.Logger)
-	app.UserHandler = handler.NewUserHandler(app.UserService, app.PRService, app.Logger)
+	// a task makes up to two sequential calls
+	syncLease := 3 * app.Config.GitHubTimeout * time.Duration(app.Config.ReviewerSyncBatchSize)
+	app.ReviewerSyncService = service.NewReviewerSyncService(app.ReviewerSyncRepo, app.IdentityRepo, codeHosts, service.ReviewerSyncConfig{
+		BatchSize:   app.Config.ReviewerSyncBatchSize,
+		MaxAttempts: app.Config.ReviewerSyncMaxAttempts,
+		RetryBase:   app.Config.ReviewerSyncRetryBase,
+		RetryMax:    app.Config.ReviewerSyncRetryMax,
+		Lease:       syncLease,
+	}, app.Logger)
+
+	publisher, err := app.newPublisher()
+	if err != nil {
+		return fmt.Errorf("failed to create outbox publisher: %w", err)
+	}
+
+	app.OutboxService = service.NewOutboxService(app.OutboxRepo, app.Tx, publisher, app.Config.OutboxBatchSize, app.Logger)
+	app.PRService = service.NewPRService(app.PRRepo, app.UserRepo, app.TeamRepo, app.OwnershipRepo, app.Tx, app.OutboxService, app.Logger)
+	app.TeamService = service.NewTeamService(app.TeamRepo, app.UserRepo, app.Tx, app.PRService, app.OutboxService, app.Logger)
+	app.UserService = service.NewUserService(app.UserRepo, app.TeamRepo, app.Tx, app.PRService, app.OutboxService, app.Logger)
+	app.OwnershipService = service.NewOwnershipService(app.OwnershipRepo, app.TeamRepo, app.UserRepo, app.Logger)
+	app.AvailabilityService = service.NewAvailabilityService(app.AvailabilityRepo, app.UserRepo, app.Tx, app.PRService, app.Logger)
+	app.StatsService = service.NewStatsService(app.StatsRepo, app.TeamRepo, app.Logger)
+	app.IntegrationService = service.NewIntegrationService(app.IdentityRepo, app.UserRepo, app.PRService, app.Logger)
+	app.FairnessService = service.NewFairnessService(app.StatsRepo, app.TeamRepo, app.UserRepo, app.PRRepo, app.Tx, app.PRService, app.Logger)
+
+	app.TeamHandler = handler.NewTeamHandler(app.TeamService, app.FairnessService, app.Logger)
+	app.UserHandler = handler.NewUserHandler(app.UserService, app.PRService, app.AvailabilityService, app.Logger)
 	app.PRHandler = handler.NewPRHandler(app.PRService, app.Logger)
+	app.OwnershipHandler = handler.NewOwnershipHandler(app.OwnershipService, app.Logger)
+	app.StatsHandler = handler.NewStatsHandler(app.StatsService, app.Logger)
+	app.WebhookHandler = handler.NewWebhookHandler(app.WebhookService, app.Logger)
+	app.IntegrationHandler = handler.NewIntegrationHandler(app.IntegrationService, app.ReviewerSyncService, handler.IntegrationConfig{
+		GitHubSecret: app.Config.GitHubWebhookSecret,
+		GitLabToken:  app.Config.GitLabWebhookToken,
+	}, app.Logger)
 
 	serverConfig := &api.ServerConfig{
 		Host:         app.Config.ServerHost,
@@ -117,6 +199,10 @@ func (app *Application) Init(ctx context.Context) error {
 		app.TeamHandler,
 		app.UserHandler,
 		app.PRHandler,
+		app.OwnershipHandler,
+		app.StatsHandler,
+		app.WebhookHandler,
+		app.IntegrationHandler,
 		app.Logger,
 	)
 
@@ -124,6 +210,51 @@ func (app *Application) Init(ctx context.Context) error {
 		return fmt.Errorf("failed to start http server: %w", err)
 	}
 
+	app.AbsenceWorker = worker.NewPeriodic("absence", app.Config.AbsenceReassignInterval, func(ctx context.Context) error {
+		_, err := app.AvailabilityService.ReassignAbsent(ctx)
+		return err
+	}, app.Logger)
+
+	if err := app.AbsenceWorker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start absence worker: %w", err)
+	}
+
+	app.SLAWorker = worker.NewPeriodic("sla", app.Config.SLAEscalationInterval, func(ctx context.Context) error {
+		_, err := app.PRService.EscalateOverdue(ctx)
+		return err
+	}, app.Logger)
+
+	if err := app.SLAWorker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start sla worker: %w", err)
+	}
+
+	app.OutboxWorker = worker.NewPeriodic("outbox", app.Config.OutboxRelayInterval, func(ctx context.Context) error {
+		_, err := app.OutboxService.Relay(ctx)
+		return err
+	}, app.Logger)
+
+	if err := app.OutboxWorker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start outbox worker: %w", err)
+	}
+
+	app.SyncWorker = worker.NewPeriodic("reviewer_sync", app.Config.ReviewerSyncInterval, func(ctx context.Context) error {
+		_, err := app.ReviewerSyncService.Sync(ctx)
+		return err
+	}, app.Logger)
+
+	if err := app.SyncWorker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start reviewer sync worker: %w", err)
+	}
+
+	app.WebhookWorker = worker.NewPeriodic("webhooks", app.Config.WebhookDeliveryInterval, func(ctx context.Context) error {
+		_, err := app.WebhookService.DeliverDue(ctx)
+		return err
+	}, app.Logger)
+
+	if err := app.WebhookWorker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start webhook worker: %w", err)
+	}
+
 	app.Logger.Info("application initialized successfully")
 	return nil
 }
@@ -137,12 +268,63 @@ func (app *Application) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	if app.AbsenceWorker != nil {
+		if err := app.AbsenceWorker.Stop(ctx); err != nil {
+			app.Logger.Error("error stopping absence worker", "error", err)
+		}
+	}
+
+	if app.SLAWorker != nil {
+		if err := app.SLAWorker.Stop(ctx); err != nil {
+			app.Logger.Error("error stopping sla worker", "error", err)
+		}
+	}
+
+	if app.OutboxWorker != nil {
+		if err := app.OutboxWorker.Stop(ctx); err != nil {
+			app.Logger.Error("error stopping outbox worker", "error", err)
+		}
+	}
+
+	if app.SyncWorker != nil {
+		if err := app.SyncWorker.Stop(ctx); err != nil {
+			app.Logger.Error("error stopping reviewer sync worker", "error", err)
+		}
+	}
+
+	if app.WebhookWorker != nil {
+		if err := app.WebhookWorker.Stop(ctx); err != nil {
+			app.Logger.Error("error stopping webhook worker", "error", err)
+		}
+	}
+
 	app.Postgres.Close()
 
 	app.Logger.Info("application shutdown completed")
 	return nil
 }
 
+// newPublisher creates the outbox publisher selected by OUTBOX_PUBLISHER.
+// Outbound webhooks and reviewer sync always get events in-process;
+// the http publisher also hands every event to an external endpoint after them.
+func (app *Application) newPublisher() (service.Publisher, error) {
+	handlers := []service.EventHandler{app.WebhookService.HandleEvent, app.ReviewerSyncService.HandleEvent}
+
+	switch app.Config.OutboxPublisher {
+	case "inprocess":
+	case "http":
+		if app.Config.OutboxHTTPURL == "" {
+			return nil, errors.New("OUTBOX_HTTP_URL is required for http publisher")
+		}
+		external := service.NewHTTPPublisher(webhook.NewSender(app.Config.WebhookTimeout), app.Config.OutboxHTTPURL, app.Config.OutboxHTTPSecret)
+		handlers = append(handlers, external.Publish)
+	default:
+		return nil, fmt.Errorf("unknown OUTBOX_PUBLISHER %q", app.Config.OutboxPublisher)
+	}
+
+	return service.NewInProcessPublisher(handlers...), nil
+}
+
 func (app *Application) Health(ctx context.Context) error {
 	if err := app.Postgres.Health(ctx); err != nil {
 		return fmt.Errorf("postgres health check failed: %w", err)
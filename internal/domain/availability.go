@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Unavailability is a period when a user can't review, e.g. a vacation.
+// Users are skipped during reviewer assignment while it lasts.
+type Unavailability struct {
+	UnavailabilityID int64      `json:"unavailability_id"`
+	UserID           string     `json:"user_id"`
+	StartsAt         time.Time  `json:"starts_at"`
+	EndsAt           time.Time  `json:"ends_at"`
+	Reason           string     `json:"reason,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+	ReassignAttempts int        `json:"reassign_attempts,omitempty"` // неудачные попытки автопереназначения
+	ReassignError    string     `json:"reassign_error,omitempty"`    // ошибка последней попытки
+}
+
+// IsCurrent reports whether the period covers the given moment.
+func (u *Unavailability) IsCurrent(at time.Time) bool {
+	return !at.Before(u.StartsAt) && at.Before(u.EndsAt)
+}
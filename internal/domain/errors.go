@@ -3,12 +3,23 @@ package domain
 import "errors"
 
 var (
-	ErrTeamExists   = errors.New("team already exists")
-	ErrTeamNotFound = errors.New("team not found")
-	ErrUserNotFound = errors.New("user not found")
-	ErrPRExists     = errors.New("pull request already exists")
-	ErrPRNotFound   = errors.New("pull request not found")
-	ErrPRMerged     = errors.New("cannot modify merged pull request")
-	ErrNotAssigned  = errors.New("user not assigned as reviewer")
-	ErrNoCandidate  = errors.New("no available reviewers in team")
+	ErrTeamExists           = errors.New("team already exists")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrPRExists             = errors.New("pull request already exists")
+	ErrPRNotFound           = errors.New("pull request not found")
+	ErrPRMerged             = errors.New("cannot modify merged pull request")
+	ErrNotAssigned          = errors.New("user not assigned as reviewer")
+	ErrNoCandidate          = errors.New("no available reviewers in team")
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrNotEnoughReviewers   = errors.New("not enough available reviewers to satisfy team minimum")
+	ErrRuleNotFound         = errors.New("ownership rule not found")
+	ErrNotEnoughApprovals   = errors.New("pull request doesn't have enough approvals")
+	ErrPRNotOpen            = errors.New("pull request is not open")
+	ErrInvalidTransition    = errors.New("pull request status transition is not allowed")
+	ErrUserInOtherTeam      = errors.New("user already belongs to another team")
+	ErrTeamHasOpenPRs       = errors.New("team has open pull requests")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrIdentityNotFound     = errors.New("external identity not found")
+	ErrSyncTaskNotFound     = errors.New("reviewer sync task not found")
 )
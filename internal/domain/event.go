@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// ReviewerEvent is an entry of the append-only reviewer assignment history.
+type ReviewerEvent struct {
+	EventID            int64             `json:"event_id"`
+	PullRequestID      string            `json:"pull_request_id"`
+	EventType          ReviewerEventType `json:"event_type"`
+	ReviewerID         string            `json:"reviewer_id"`
+	PreviousReviewerID string            `json:"previous_reviewer_id,omitempty"` // только для REASSIGNED
+	ActorID            string            `json:"actor_id"`
+	Reason             string            `json:"reason"`
+	CreatedAt          time.Time         `json:"created_at"`
+}
+
+type ReviewerEventType string
+
+const (
+	ReviewerEventAssigned   ReviewerEventType = "ASSIGNED"
+	ReviewerEventReassigned ReviewerEventType = "REASSIGNED"
+	ReviewerEventUnassigned ReviewerEventType = "UNASSIGNED"
+	ReviewerEventMergedWith ReviewerEventType = "MERGED_WITH"
+	ReviewerEventClosedWith ReviewerEventType = "CLOSED_WITH"
+	ReviewerEventReviewed   ReviewerEventType = "REVIEWED" // reason содержит решение
+)
+
+// SystemActor is recorded as actor of changes not initiated by a user.
+const SystemActor = "system"
+
+// Reasons recorded for automatic reviewer assignments.
+const (
+	ReasonCodeOwner    = "code_owner"
+	ReasonTeamMember   = "team_member"
+	ReasonFallbackTeam = "fallback_team"
+	ReasonReassigned   = "reassigned"
+	ReasonMerged       = "merged"
+	ReasonClosed       = "closed"
+	ReasonDeactivated  = "user_deactivated"
+	ReasonAtCapacity   = "at_capacity"
+	ReasonRemoved      = "removed_from_team"
+	ReasonMovedTeam    = "moved_to_other_team"
+	ReasonUnavailable  = "user_unavailable"
+	ReasonSLAExpired   = "review_sla_expired"
+	ReasonRebalanced   = "rebalanced"
+	ReasonImported     = "imported"
+)
@@ -0,0 +1,45 @@
+package domain
+
+// FairnessStatus classifies a member's review load against the team average.
+type FairnessStatus string
+
+const (
+	FairnessOK          FairnessStatus = "ok"
+	FairnessOverloaded  FairnessStatus = "overloaded"
+	FairnessUnderloaded FairnessStatus = "underloaded"
+	FairnessInactive    FairnessStatus = "inactive" // не участвует в расчёте
+)
+
+// FairnessEntry compares a member's actual share of review assignments with the expected one.
+// Active members are expected to receive an equal share.
+type FairnessEntry struct {
+	UserID        string         `json:"user_id"`
+	Username      string         `json:"username"`
+	IsActive      bool           `json:"is_active"`
+	Assigned      int            `json:"assigned"`       // назначения в окне
+	OpenReviews   int            `json:"open_reviews"`   // OPEN PR на ревью сейчас
+	Share         float64        `json:"share"`          // доля от всех назначений команды
+	ExpectedShare float64        `json:"expected_share"` // 1/N для активных, 0 для остальных
+	Deviation     float64        `json:"deviation"`      // (assigned - mean) / stddev
+	Status        FairnessStatus `json:"status"`
+}
+
+// RebalanceMove moves an open review from one team member to another.
+type RebalanceMove struct {
+	PullRequestID  string `json:"pull_request_id"`
+	FromReviewerID string `json:"from_reviewer_id"`
+	ToReviewerID   string `json:"to_reviewer_id"`
+}
+
+// FairnessReport describes review load distribution within a team.
+// Proposals are only suggestions; nothing changes until they are applied.
+type FairnessReport struct {
+	TeamName  string          `json:"team_name"`
+	Window    StatsWindow     `json:"window"`
+	Threshold float64         `json:"threshold"` // порог отклонения в стандартных отклонениях
+	Total     int             `json:"total"`     // назначения активных участников в окне
+	Mean      float64         `json:"mean"`
+	StdDev    float64         `json:"std_dev"`
+	Members   []FairnessEntry `json:"members"`
+	Proposals []RebalanceMove `json:"proposals"`
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider is a git hosting sending pull request webhooks.
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+)
+
+// IsValid reports whether the provider is a supported one.
+func (p Provider) IsValid() bool {
+	switch p {
+	case ProviderGitHub, ProviderGitLab:
+		return true
+	}
+	return false
+}
+
+// ExternalIdentity maps a login on a git hosting to a user.
+type ExternalIdentity struct {
+	Provider  Provider  `json:"provider"`
+	Login     string    `json:"login"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExternalAction is a pull request change reported by a git hosting.
+type ExternalAction string
+
+const (
+	ExternalOpened   ExternalAction = "opened"   // PR создан (возможно, черновиком)
+	ExternalReady    ExternalAction = "ready"    // черновик готов к ревью
+	ExternalClosed   ExternalAction = "closed"   // закрыт без merge
+	ExternalMerged   ExternalAction = "merged"   // закрыт с merge
+	ExternalReopened ExternalAction = "reopened" // открыт повторно
+)
+
+// ExternalPREvent is a provider-independent pull request webhook.
+type ExternalPREvent struct {
+	Provider    Provider
+	Action      ExternalAction
+	Repository  string // owner/repo или group/project
+	Number      int    // номер PR в репозитории (iid для GitLab)
+	Title       string
+	Draft       bool
+	AuthorLogin string
+	ActorLogin  string // кто совершил действие
+}
+
+// PullRequestID returns the ID of the pull request the event refers to.
+func (e ExternalPREvent) PullRequestID() string {
+	return ExternalPRRef{Provider: e.Provider, Repository: e.Repository, Number: e.Number}.PullRequestID()
+}
+
+// ExternalPRRef identifies a pull request on a git hosting.
+type ExternalPRRef struct {
+	Provider   Provider
+	Repository string
+	Number     int
+}
+
+// PullRequestID returns the ID of the pull request,
+// e.g. "github:owner/repo#42" or "gitlab:group/project!42".
+func (r ExternalPRRef) PullRequestID() string {
+	return fmt.Sprintf("%s:%s%s%d", r.Provider, r.Repository, r.separator(), r.Number)
+}
+
+func (r ExternalPRRef) separator() string {
+	if r.Provider == ProviderGitLab {
+		return "!"
+	}
+	return "#"
+}
+
+// ParseExternalPRRef parses a pull request ID created from a git hosting webhook.
+// Reports false for IDs of pull requests created through the API.
+func ParseExternalPRRef(prID string) (ExternalPRRef, bool) {
+	provider, rest, found := strings.Cut(prID, ":")
+	if !found || !Provider(provider).IsValid() {
+		return ExternalPRRef{}, false
+	}
+
+	ref := ExternalPRRef{Provider: Provider(provider)}
+
+	i := strings.LastIndex(rest, ref.separator())
+	if i <= 0 {
+		return ExternalPRRef{}, false
+	}
+
+	number, err := strconv.Atoi(rest[i+1:])
+	if err != nil || number <= 0 {
+		return ExternalPRRef{}, false
+	}
+
+	ref.Repository = rest[:i]
+	ref.Number = number
+
+	return ref, true
+}
+
+// ReviewerSyncTask is a reviewer change to be copied to the git hosting.
+// Tasks failed for good are kept as dead letters with FailedAt set.
+type ReviewerSyncTask struct {
+	TaskID           int64      `json:"task_id"`
+	PullRequestID    string     `json:"pull_request_id"`
+	AddReviewerID    string     `json:"add_reviewer_id,omitempty"`    // запросить ревью у пользователя
+	RemoveReviewerID string     `json:"remove_reviewer_id,omitempty"` // снять запрос ревью
+	Attempts         int        `json:"attempts"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"` // нет у dead letters
+	LastError        string     `json:"last_error,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	FailedAt         *time.Time `json:"failed_at,omitempty"`
+}
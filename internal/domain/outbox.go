@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a domain event stored in the outbox until it's published.
+// Payload is the JSON-encoded Event.
+type OutboxEvent struct {
+	EventID     int64           `json:"event_id"`
+	Type        EventType       `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// OwnershipRule is a CODEOWNERS-style rule: files matching Pattern are owned by Owners.
+// When several rules of a team match a file, the most recently created one wins.
+type OwnershipRule struct {
+	RuleID    int64      `json:"rule_id"`
+	TeamName  string     `json:"team_name"`
+	Pattern   string     `json:"pattern"`
+	Owners    []string   `json:"owners"`
+	CreatedAt *time.Time `json:"created_at"`
+}
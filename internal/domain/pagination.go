@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor is a keyset pagination position: the sort timestamp and ID of the last
+// item of the previous page.
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// Encode returns an opaque URL-safe representation of the cursor.
+func (c Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+
+	return &Cursor{Time: parsed, ID: id}, nil
+}
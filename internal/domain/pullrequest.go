@@ -6,22 +6,168 @@ type PullRequest struct {
 	PullRequestID     string     `json:"pull_request_id"`
 	PullRequestName   string     `json:"pull_request_name"`
 	AuthorID          string     `json:"author_id"`
-	Status            PRStatus   `json:"status"`             // OPEN или MERGED
-	AssignedReviewers []string   `json:"assigned_reviewers"` // до 2 user_id
+	Status            PRStatus   `json:"status"`             // DRAFT, OPEN, MERGED или CLOSED
+	AssignedReviewers []string   `json:"assigned_reviewers"` // до max_reviewers команды автора
+	Reviewers         []Reviewer `json:"reviewers"`          // те же ревьюеры с деталями назначения
 	CreatedAt         *time.Time `json:"created_at"`
 	MergedAt          *time.Time `json:"merged_at"`
+	ClosedAt          *time.Time `json:"closed_at"`
+}
+
+// Reviewer is a reviewer assignment on a pull request.
+type Reviewer struct {
+	UserID      string         `json:"user_id"`
+	TeamName    string         `json:"team_name"` // команда, из которой выбран ревьюер
+	Decision    ReviewDecision `json:"decision"`
+	AssignedAt  *time.Time     `json:"assigned_at,omitempty"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	Reason      string         `json:"reason"`                // причина назначения, пишется в историю
+	Explanation string         `json:"explanation,omitempty"` // почему выбран именно этот ревьюер
+}
+
+// ReviewDecision is the verdict of a single reviewer.
+type ReviewDecision string
+
+const (
+	ReviewDecisionPending          ReviewDecision = "PENDING"
+	ReviewDecisionApproved         ReviewDecision = "APPROVED"
+	ReviewDecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+)
+
+// IsValid reports whether the decision is a known one.
+func (d ReviewDecision) IsValid() bool {
+	switch d {
+	case ReviewDecisionPending, ReviewDecisionApproved, ReviewDecisionChangesRequested:
+		return true
+	}
+	return false
+}
+
+// Approvals returns the number of reviewers who approved the pull request.
+func (pr *PullRequest) Approvals() int {
+	approvals := 0
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Decision == ReviewDecisionApproved {
+			approvals++
+		}
+	}
+	return approvals
 }
 
 type PRStatus string
 
 const (
+	PRStatusDraft  PRStatus = "DRAFT" // ревьюеры не назначаются до перевода в OPEN
 	PRStatusOpen   PRStatus = "OPEN"
 	PRStatusMerged PRStatus = "MERGED"
+	PRStatusClosed PRStatus = "CLOSED" // закрыт без merge, ревьюеры освобождены
 )
 
+// IsValid reports whether the status is a known one.
+func (s PRStatus) IsValid() bool {
+	switch s {
+	case PRStatusDraft, PRStatusOpen, PRStatusMerged, PRStatusClosed:
+		return true
+	}
+	return false
+}
+
+// prTransitions lists allowed status changes.
+var prTransitions = map[PRStatus][]PRStatus{
+	PRStatusDraft:  {PRStatusOpen, PRStatusClosed},
+	PRStatusOpen:   {PRStatusMerged, PRStatusClosed},
+	PRStatusClosed: {PRStatusOpen},
+}
+
+// CanTransitionTo reports whether a pull request in status s may move to next.
+func (s PRStatus) CanTransitionTo(next PRStatus) bool {
+	for _, allowed := range prTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type PullRequestShort struct {
-	PullRequestID   string   `json:"pull_request_id"`
-	PullRequestName string   `json:"pull_request_name"`
-	AuthorID        string   `json:"author_id"`
-	Status          PRStatus `json:"status"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Status          PRStatus   `json:"status"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+}
+
+// Reassignment describes a reviewer replaced on a pull request.
+// Empty NewReviewerID means no replacement was found and the reviewer was unassigned.
+type Reassignment struct {
+	PullRequestID string             `json:"pull_request_id"`
+	OldReviewerID string             `json:"old_reviewer_id"`
+	NewReviewerID string             `json:"new_reviewer_id,omitempty"`
+	Reason        UnreassignedReason `json:"reason,omitempty"` // только если замены нет
+}
+
+// SplitReassignments separates reviews handed over to a new reviewer
+// from those left without one.
+func SplitReassignments(all []Reassignment) (reassigned, unreassigned []Reassignment) {
+	reassigned = []Reassignment{}
+	unreassigned = []Reassignment{}
+	for _, reassignment := range all {
+		if reassignment.NewReviewerID == "" {
+			unreassigned = append(unreassigned, reassignment)
+		} else {
+			reassigned = append(reassigned, reassignment)
+		}
+	}
+	return reassigned, unreassigned
+}
+
+// UnreassignedReason explains why no replacement reviewer was found.
+type UnreassignedReason string
+
+const (
+	// UnreassignedNoCandidate means the teams have no other active members.
+	UnreassignedNoCandidate UnreassignedReason = "NO_CANDIDATE"
+	// UnreassignedAllAtCapacity means every available member reached their open review cap.
+	UnreassignedAllAtCapacity UnreassignedReason = "ALL_AT_CAPACITY"
+	// UnreassignedNobodyAvailable means every active member is absent right now.
+	UnreassignedNobodyAvailable UnreassignedReason = "NOBODY_AVAILABLE"
+)
+
+// OverdueReview is a pending review held longer than the review SLA of the author's team.
+type OverdueReview struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	TeamName        string    `json:"team_name"` // команда автора, чей SLA нарушен
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignedAt      time.Time `json:"assigned_at"`
+	DueAt           time.Time `json:"due_at"`
+}
+
+// PRFilter narrows down pull request listings. Empty fields and nil bounds match everything.
+// Date ranges are half-open: [From, To).
+type PRFilter struct {
+	Status      PRStatus   // статус PR
+	TeamName    string     // команда автора
+	AuthorID    string     // автор
+	ReviewerID  string     // один из текущих ревьюеров
+	Query       string     // начало названия, без учёта регистра
+	CreatedFrom *time.Time // created_at >= CreatedFrom
+	CreatedTo   *time.Time // created_at < CreatedTo
+	MergedFrom  *time.Time // merged_at >= MergedFrom
+	MergedTo    *time.Time // merged_at < MergedTo
+}
+
+// ReviewPage is a page of pull requests a user reviews, newest first.
+// NextCursor is empty on the last page.
+type ReviewPage struct {
+	PullRequests []*PullRequestShort `json:"pull_requests"`
+	NextCursor   string              `json:"next_cursor,omitempty"`
+}
+
+// PRPage is a page of pull requests, newest first.
+// NextCursor is empty on the last page.
+type PRPage struct {
+	PullRequests []*PullRequest `json:"pull_requests"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
 }
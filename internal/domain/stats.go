@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// StatsWindow limits statistics to events within [From, To). Nil bounds are open.
+type StatsWindow struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// UserStats aggregates review activity of a single user.
+// OpenReviews is a current snapshot and doesn't depend on the window.
+type UserStats struct {
+	UserID                string `json:"user_id"`
+	Username              string `json:"username"`
+	TeamName              string `json:"team_name"`
+	IsActive              bool   `json:"is_active"`
+	Assigned              int    `json:"assigned"`               // назначения в окне, включая потом переназначенные
+	OpenReviews           int    `json:"open_reviews"`           // OPEN PR на ревью сейчас
+	MergedReviewed        int    `json:"merged_reviewed"`        // смерженные в окне PR, где пользователь ревьювер
+	MergedAuthored        int    `json:"merged_authored"`        // смерженные в окне PR пользователя
+	ReassignmentsReceived int    `json:"reassignments_received"` // ревью, переданные пользователю
+	ReassignmentsGiven    int    `json:"reassignments_given"`    // ревью, переданные от пользователя
+}
+
+// TeamStats aggregates review activity of a team by current membership.
+// OpenPRs and OpenReviews are current snapshots and don't depend on the window.
+type TeamStats struct {
+	TeamName      string `json:"team_name"`
+	Members       int    `json:"members"`
+	ActiveMembers int    `json:"active_members"`
+	PRsCreated    int    `json:"prs_created"`   // PR участников, созданные в окне
+	PRsMerged     int    `json:"prs_merged"`    // PR участников, смерженные в окне
+	OpenPRs       int    `json:"open_prs"`      // OPEN PR участников сейчас
+	Assigned      int    `json:"assigned"`      // назначения участников в окне
+	OpenReviews   int    `json:"open_reviews"`  // OPEN PR на ревью у участников сейчас
+	Reassignments int    `json:"reassignments"` // ревью, переданные участникам в окне
+}
+
+// CycleTimeGroup is the dimension cycle-time metrics are grouped by.
+type CycleTimeGroup string
+
+const (
+	CycleTimeByTeam     CycleTimeGroup = "team"
+	CycleTimeByAuthor   CycleTimeGroup = "author"
+	CycleTimeByReviewer CycleTimeGroup = "reviewer"
+)
+
+// IsValid reports whether the grouping is a known one.
+func (g CycleTimeGroup) IsValid() bool {
+	switch g {
+	case CycleTimeByTeam, CycleTimeByAuthor, CycleTimeByReviewer:
+		return true
+	}
+	return false
+}
+
+// Percentiles of a duration in seconds. Nil when there is no data.
+type Percentiles struct {
+	P50 *float64 `json:"p50"`
+	P90 *float64 `json:"p90"`
+	P99 *float64 `json:"p99"`
+}
+
+// CycleTimeStats holds turnaround metrics of merged pull requests of one group.
+// Teams are the author's team; reviewer groups see cycle time of PRs they reviewed.
+type CycleTimeStats struct {
+	Group      string      `json:"group"`               // team_name, author_id или reviewer_id
+	PRs        int         `json:"prs"`                 // смерженные PR
+	CycleTime  Percentiles `json:"cycle_time_seconds"`  // создание → merge
+	Reviews    int         `json:"reviews"`             // ревью смерженных PR
+	ReviewTime Percentiles `json:"review_time_seconds"` // назначение ревьювера → merge
+}
@@ -1,12 +1,101 @@
 package domain
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName string `json:"team_name"`
+	TeamSettings
+	Members []TeamMember `json:"members"`
 }
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
+}
+
+// TeamSettings holds per-team reviewer assignment configuration.
+type TeamSettings struct {
+	ReviewerStrategy      ReviewerStrategy `json:"reviewer_strategy"`
+	MinReviewers          int              `json:"min_reviewers"`
+	MaxReviewers          int              `json:"max_reviewers"`
+	FallbackTeams         []string         `json:"fallback_teams"`           // в порядке приоритета
+	RequiredApprovals     int              `json:"required_approvals"`       // 0 - merge без апрувов
+	AutoReassignOnAbsence bool             `json:"auto_reassign_on_absence"` // переназначать ревью отсутствующих
+	ReviewSLAHours        int              `json:"review_sla_hours"`         // 0 - без SLA
+	EscalateOverdue       bool             `json:"escalate_overdue"`         // переназначать просроченные ревью
+}
+
+// TeamSettingsUpdate is a partial update of TeamSettings. Nil fields are left unchanged.
+type TeamSettingsUpdate struct {
+	ReviewerStrategy      *ReviewerStrategy `json:"reviewer_strategy,omitempty"`
+	MinReviewers          *int              `json:"min_reviewers,omitempty"`
+	MaxReviewers          *int              `json:"max_reviewers,omitempty"`
+	FallbackTeams         *[]string         `json:"fallback_teams,omitempty"`
+	RequiredApprovals     *int              `json:"required_approvals,omitempty"`
+	AutoReassignOnAbsence *bool             `json:"auto_reassign_on_absence,omitempty"`
+	ReviewSLAHours        *int              `json:"review_sla_hours,omitempty"`
+	EscalateOverdue       *bool             `json:"escalate_overdue,omitempty"`
+}
+
+// Apply copies every non-nil field of the update into settings.
+func (u TeamSettingsUpdate) Apply(settings *TeamSettings) {
+	if u.ReviewerStrategy != nil {
+		settings.ReviewerStrategy = *u.ReviewerStrategy
+	}
+	if u.MinReviewers != nil {
+		settings.MinReviewers = *u.MinReviewers
+	}
+	if u.MaxReviewers != nil {
+		settings.MaxReviewers = *u.MaxReviewers
+	}
+	if u.FallbackTeams != nil {
+		settings.FallbackTeams = *u.FallbackTeams
+	}
+	if u.RequiredApprovals != nil {
+		settings.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.AutoReassignOnAbsence != nil {
+		settings.AutoReassignOnAbsence = *u.AutoReassignOnAbsence
+	}
+	if u.ReviewSLAHours != nil {
+		settings.ReviewSLAHours = *u.ReviewSLAHours
+	}
+	if u.EscalateOverdue != nil {
+		settings.EscalateOverdue = *u.EscalateOverdue
+	}
+}
+
+// DefaultTeamSettings returns settings used for teams that don't specify their own.
+func DefaultTeamSettings() TeamSettings {
+	return TeamSettings{
+		ReviewerStrategy:      ReviewerStrategyRandom,
+		MinReviewers:          0,
+		MaxReviewers:          2,
+		FallbackTeams:         []string{},
+		RequiredApprovals:     0,
+		AutoReassignOnAbsence: false,
+		ReviewSLAHours:        0,
+		EscalateOverdue:       false,
+	}
+}
+
+type ReviewerStrategy string
+
+const (
+	ReviewerStrategyRandom         ReviewerStrategy = "random"
+	ReviewerStrategyRoundRobin     ReviewerStrategy = "round_robin"
+	ReviewerStrategyLeastLoaded    ReviewerStrategy = "least_loaded"
+	ReviewerStrategyWeightedRandom ReviewerStrategy = "weighted_random"
+)
+
+// IsValid reports whether the strategy is one of the built-in ones.
+func (s ReviewerStrategy) IsValid() bool {
+	switch s {
+	case ReviewerStrategyRandom,
+		ReviewerStrategyRoundRobin,
+		ReviewerStrategyLeastLoaded,
+		ReviewerStrategyWeightedRandom:
+		return true
+	}
+	return false
 }
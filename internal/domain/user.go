@@ -1,8 +1,78 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"` // nil - без ограничения
+	Timezone       string `json:"timezone"`                   // IANA, например Europe/Moscow
+	WorkStart      string `json:"work_start,omitempty"`       // HH:MM в timezone пользователя
+	WorkEnd        string `json:"work_end,omitempty"`         // HH:MM, раньше WorkStart - смена через полночь
+}
+
+// UserProfile is a user together with their current review load and authorship.
+type UserProfile struct {
+	User
+	OpenReviews int `json:"open_reviews"` // OPEN PR на ревью сейчас
+	AuthoredPRs int `json:"authored_prs"` // все PR пользователя
+}
+
+// HasWorkingHours reports whether the user has working hours configured.
+func (u *User) HasWorkingHours() bool {
+	return u.WorkStart != "" && u.WorkEnd != ""
+}
+
+// InWorkingHours reports whether the moment falls within user's working hours
+// in their timezone. Users without working hours are always considered working.
+func (u *User) InWorkingHours(at time.Time) bool {
+	if !u.HasWorkingHours() {
+		return true
+	}
+
+	start, errStart := ParseClock(u.WorkStart)
+	end, errEnd := ParseClock(u.WorkEnd)
+	if errStart != nil || errEnd != nil {
+		return true
+	}
+
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := at.In(location)
+	now := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if start <= end {
+		return now >= start && now < end
+	}
+	// Overnight shift, e.g. 22:00-06:00
+	return now >= start || now < end
+}
+
+// ParseClock parses a HH:MM wall clock time into the offset from midnight.
+func ParseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// ReviewerCandidate is an active user considered for review assignment
+// together with the number of OPEN pull requests they currently review.
+type ReviewerCandidate struct {
+	*User
+	OpenReviews int
+}
+
+// AtCapacity reports whether the candidate reached their open review cap.
+func (c *ReviewerCandidate) AtCapacity() bool {
+	return c.MaxOpenReviews != nil && c.OpenReviews >= *c.MaxOpenReviews
 }
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType is the type of a domain event delivered to webhook subscribers.
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventPRMerged           EventType = "pr.merged"
+	EventPRClosed           EventType = "pr.closed"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventReviewerUnassigned EventType = "reviewer.unassigned"
+	EventUserDeactivated    EventType = "user.deactivated"
+)
+
+// IsValid reports whether the event type is a known one.
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventPRCreated, EventPRMerged, EventPRClosed,
+		EventReviewerAssigned, EventReviewerReassigned, EventReviewerUnassigned,
+		EventUserDeactivated:
+		return true
+	}
+	return false
+}
+
+// Event is a domain event. Data is the event payload, specific to the type.
+type Event struct {
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// NewEvent creates an event that occurred now.
+func NewEvent(eventType EventType, data any) Event {
+	return Event{Type: eventType, OccurredAt: time.Now().UTC(), Data: data}
+}
+
+// PREventData is the payload of pr.created, pr.merged and pr.closed.
+type PREventData struct {
+	PR *PullRequest `json:"pr"`
+}
+
+// ReviewerEventData is the payload of reviewer.assigned, reviewer.reassigned
+// and reviewer.unassigned.
+type ReviewerEventData struct {
+	PullRequestID      string `json:"pull_request_id"`
+	ReviewerID         string `json:"reviewer_id"`
+	PreviousReviewerID string `json:"previous_reviewer_id,omitempty"` // только для reviewer.reassigned
+	Reason             string `json:"reason"`
+}
+
+// UserEventData is the payload of user.deactivated.
+type UserEventData struct {
+	User *User `json:"user"`
+}
+
+// WebhookSubscription is an endpoint notified about events of the given types.
+// Deliveries are signed with the secret, which is never returned by the API.
+type WebhookSubscription struct {
+	SubscriptionID int64       `json:"subscription_id"`
+	URL            string      `json:"url"`
+	Secret         string      `json:"-"`
+	EventTypes     []EventType `json:"event_types"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"   // ждёт первой или повторной попытки
+	DeliveryDelivered DeliveryStatus = "DELIVERED" // получатель ответил 2xx
+	DeliveryFailed    DeliveryStatus = "FAILED"    // попытки исчерпаны
+)
+
+// WebhookDelivery is a single event sent to a single subscription.
+type WebhookDelivery struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	// URL and Secret of the subscription, filled when a delivery is claimed for sending
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
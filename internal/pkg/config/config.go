@@ -46,6 +46,39 @@ type Config struct {
 	ServerReadTimeout  time.Duration `env:"SERVER_READ_TIMEOUT" env-default:"30s"`
 	ServerWriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT" env-default:"30s"`
 	ServerIdleTimeout  time.Duration `env:"SERVER_IDLE_TIMEOUT" env-default:"60s"`
+
+	// background jobs
+	AbsenceReassignInterval time.Duration `env:"ABSENCE_REASSIGN_INTERVAL" env-default:"1m"`
+	SLAEscalationInterval   time.Duration `env:"SLA_ESCALATION_INTERVAL" env-default:"5m"`
+	WebhookDeliveryInterval time.Duration `env:"WEBHOOK_DELIVERY_INTERVAL" env-default:"5s"`
+	OutboxRelayInterval     time.Duration `env:"OUTBOX_RELAY_INTERVAL" env-default:"1s"`
+	ReviewerSyncInterval    time.Duration `env:"REVIEWER_SYNC_INTERVAL" env-default:"10s"`
+
+	// outbox
+	OutboxBatchSize  int    `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	OutboxPublisher  string `env:"OUTBOX_PUBLISHER" env-default:"inprocess"` // inprocess или http (inprocess + внешний endpoint)
+	OutboxHTTPURL    string `env:"OUTBOX_HTTP_URL"`
+	OutboxHTTPSecret string `env:"OUTBOX_HTTP_SECRET"`
+
+	// webhooks
+	WebhookTimeout     time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	WebhookBatchSize   int           `env:"WEBHOOK_BATCH_SIZE" env-default:"100"`
+	WebhookMaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+	WebhookRetryBase   time.Duration `env:"WEBHOOK_RETRY_BASE" env-default:"30s"`
+	WebhookRetryMax    time.Duration `env:"WEBHOOK_RETRY_MAX" env-default:"1h"`
+
+	// git hosting integrations, empty secret disables the endpoint
+	GitHubWebhookSecret string `env:"GITHUB_WEBHOOK_SECRET"`
+	GitLabWebhookToken  string `env:"GITLAB_WEBHOOK_TOKEN"`
+
+	// reviewer sync to GitHub, empty token disables it
+	GitHubAPIURL            string        `env:"GITHUB_API_URL" env-default:"https://api.github.com"`
+	GitHubToken             string        `env:"GITHUB_TOKEN"`
+	GitHubTimeout           time.Duration `env:"GITHUB_TIMEOUT" env-default:"10s"`
+	ReviewerSyncBatchSize   int           `env:"REVIEWER_SYNC_BATCH_SIZE" env-default:"50"`
+	ReviewerSyncMaxAttempts int           `env:"REVIEWER_SYNC_MAX_ATTEMPTS" env-default:"6"`
+	ReviewerSyncRetryBase   time.Duration `env:"REVIEWER_SYNC_RETRY_BASE" env-default:"1m"`
+	ReviewerSyncRetryMax    time.Duration `env:"REVIEWER_SYNC_RETRY_MAX" env-default:"1h"`
 }
 
 func New() (*Config, error) {
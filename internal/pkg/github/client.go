@@ -0,0 +1,113 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the REST API of github.com; GitHub Enterprise Server uses https://<host>/api/v3.
+const DefaultBaseURL = "https://api.github.com"
+
+const apiVersion = "2022-11-28"
+
+// APIError is a non-2xx response of the API.
+type APIError struct {
+	StatusCode  int
+	Message     string
+	RateLimited bool // запрос отклонён из-за исчерпанного лимита
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github api: %d %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether repeating the request may succeed:
+// rate limits and server errors are temporary, other client errors are not.
+func (e *APIError) Retryable() bool {
+	return e.RateLimited || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Client calls the GitHub REST API with a token.
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type reviewersRequest struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// RequestReviewers requests reviews of the users from a pull request.
+// repository is "owner/repo".
+func (c *Client) RequestReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return c.do(ctx, http.MethodPost, reviewersPath(repository, number), reviewersRequest{Reviewers: logins})
+}
+
+// RemoveReviewers cancels review requests of the users on a pull request.
+func (c *Client) RemoveReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return c.do(ctx, http.MethodDelete, reviewersPath(repository, number), reviewersRequest{Reviewers: logins})
+}
+
+func reviewersPath(repository string, number int) string {
+	return fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repository, number)
+}
+
+// do sends a JSON request and turns non-2xx responses into APIError.
+func (c *Client) do(ctx context.Context, method, path string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{
+		StatusCode:  resp.StatusCode,
+		Message:     http.StatusText(resp.StatusCode),
+		RateLimited: resp.Header.Get("X-RateLimit-Remaining") == "0",
+	}
+
+	var errBody struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(respBody, &errBody) == nil && errBody.Message != "" {
+		apiErr.Message = errBody.Message
+	}
+
+	return apiErr
+}
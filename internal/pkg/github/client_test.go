@@ -0,0 +1,155 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRequestReviewers(t *testing.T) {
+	var got struct {
+		method  string
+		path    string
+		headers http.Header
+		body    reviewersRequest
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method = r.Method
+		got.path = r.URL.Path
+		got.headers = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "token", time.Second)
+	if err := client.RequestReviewers(context.Background(), "acme/backend", 42, []string{"octocat"}); err != nil {
+		t.Fatalf("RequestReviewers() error = %v", err)
+	}
+
+	if got.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", got.method)
+	}
+	if got.path != "/repos/acme/backend/pulls/42/requested_reviewers" {
+		t.Errorf("path = %s", got.path)
+	}
+	if !reflect.DeepEqual(got.body.Reviewers, []string{"octocat"}) {
+		t.Errorf("reviewers = %v, want [octocat]", got.body.Reviewers)
+	}
+
+	wantHeaders := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer token",
+		"Content-Type":         "application/json",
+		"X-GitHub-Api-Version": apiVersion,
+	}
+	for name, want := range wantHeaders {
+		if value := got.headers.Get(name); value != want {
+			t.Errorf("header %s = %q, want %q", name, value, want)
+		}
+	}
+}
+
+func TestRemoveReviewers(t *testing.T) {
+	var method, path string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", time.Second)
+	if err := client.RemoveReviewers(context.Background(), "acme/backend", 42, []string{"octocat"}); err != nil {
+		t.Fatalf("RemoveReviewers() error = %v", err)
+	}
+
+	if method != http.MethodDelete || path != "/repos/acme/backend/pulls/42/requested_reviewers" {
+		t.Errorf("request = %s %s", method, path)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		header      http.Header
+		body        string
+		wantMessage string
+		retryable   bool
+	}{
+		{
+			name:        "server error",
+			status:      http.StatusBadGateway,
+			wantMessage: "Bad Gateway",
+			retryable:   true,
+		},
+		{
+			name:        "too many requests",
+			status:      http.StatusTooManyRequests,
+			wantMessage: "Too Many Requests",
+			retryable:   true,
+		},
+		{
+			name:        "primary rate limit",
+			status:      http.StatusForbidden,
+			header:      http.Header{"X-Ratelimit-Remaining": {"0"}},
+			body:        `{"message":"API rate limit exceeded"}`,
+			wantMessage: "API rate limit exceeded",
+			retryable:   true,
+		},
+		{
+			name:        "forbidden",
+			status:      http.StatusForbidden,
+			header:      http.Header{"X-Ratelimit-Remaining": {"4999"}},
+			body:        `{"message":"Resource not accessible by integration"}`,
+			wantMessage: "Resource not accessible by integration",
+			retryable:   false,
+		},
+		{
+			name:        "unprocessable",
+			status:      http.StatusUnprocessableEntity,
+			body:        `{"message":"Reviews may only be requested from collaborators."}`,
+			wantMessage: "Reviews may only be requested from collaborators.",
+			retryable:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, values := range tt.header {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "token", time.Second)
+			err := client.RequestReviewers(context.Background(), "acme/backend", 42, []string{"octocat"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("RequestReviewers() error = %v, want APIError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Retryable() != tt.retryable {
+				t.Errorf("Retryable() = %v, want %v", apiErr.Retryable(), tt.retryable)
+			}
+		})
+	}
+}
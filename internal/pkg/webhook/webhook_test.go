@@ -0,0 +1,32 @@
+package webhook
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"action":"opened","number":42}`)
+	signature := Sign("secret", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid signature", secret: "secret", body: body, signature: signature, want: true},
+		{name: "known value", secret: "It's a Secret to Everybody", body: []byte("Hello, World!"),
+			signature: "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", want: true},
+		{name: "wrong secret", secret: "other", body: body, signature: signature, want: false},
+		{name: "tampered body", secret: "secret", body: []byte(`{"action":"closed","number":42}`), signature: signature, want: false},
+		{name: "missing prefix", secret: "secret", body: body, signature: signature[len("sha256="):], want: false},
+		{name: "empty signature", secret: "secret", body: body, signature: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"time"
+)
+
+// Job is a unit of background work run by Periodic.
+type Job func(ctx context.Context) error
+
+// Periodic runs a job at a fixed interval until stopped.
+// Runs never overlap: the next one starts an interval after the previous one ends.
+type Periodic struct {
+	name     string
+	interval time.Duration
+	job      Job
+	logger   *logger.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewPeriodic(name string, interval time.Duration, job Job, logger *logger.Logger) *Periodic {
+	return &Periodic{
+		name:     name,
+		interval: interval,
+		job:      job,
+		logger:   logger.Component("worker/" + name),
+	}
+}
+
+// Start launches the worker in background.
+func (p *Periodic) Start(_ context.Context) error {
+	// detached from the caller: the worker lives until Stop
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	p.logger.Info("starting worker", "interval", p.interval)
+
+	go p.run(ctx)
+
+	return nil
+}
+
+// Stop signals the worker to finish and waits for the current run to end or ctx to expire.
+func (p *Periodic) Stop(ctx context.Context) error {
+	if p.cancel == nil {
+		return nil
+	}
+
+	p.cancel()
+
+	select {
+	case <-p.done:
+		p.logger.Info("worker stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Periodic) run(ctx context.Context) {
+	defer close(p.done)
+
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if err := p.job(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("worker run failed", "error", err)
+		}
+
+		timer.Reset(p.interval)
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type AvailabilityRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewAvailabilityRepo(db *pgxpool.Pool, logger *logger.Logger) *AvailabilityRepo {
+	return &AvailabilityRepo{
+		db:     db,
+		logger: logger.Component("repository/availability"),
+	}
+}
+
+// Create persists an unavailability period of a user.
+func (r *AvailabilityRepo) Create(ctx context.Context, period *domain.Unavailability) (*domain.Unavailability, error) {
+	created := *period
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO user_unavailability (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING unavailability_id, created_at
+	`, period.UserID, period.StartsAt, period.EndsAt, period.Reason).Scan(&created.UnavailabilityID, &created.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("insert unavailability: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ListByUser retrieves current and upcoming unavailability periods of a user.
+// Returns empty slice if user has none.
+func (r *AvailabilityRepo) ListByUser(ctx context.Context, userID string) ([]*domain.Unavailability, error) {
+	query := `
+		SELECT unavailability_id, user_id, starts_at, ends_at, reason, created_at,
+		       reassign_attempts, reassign_error
+		FROM user_unavailability
+		WHERE user_id = $1 AND ends_at > NOW()
+		ORDER BY starts_at
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query unavailability: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []*domain.Unavailability{}
+	for rows.Next() {
+		period := &domain.Unavailability{}
+		if err := rows.Scan(
+			&period.UnavailabilityID,
+			&period.UserID,
+			&period.StartsAt,
+			&period.EndsAt,
+			&period.Reason,
+			&period.CreatedAt,
+			&period.ReassignAttempts,
+			&period.ReassignError,
+		); err != nil {
+			return nil, fmt.Errorf("scan unavailability: %w", err)
+		}
+		periods = append(periods, period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return periods, nil
+}
+
+// ClaimStarted locks one started, not yet processed unavailability period of a member
+// of a team with auto_reassign_on_absence enabled. Periods whose last attempt failed
+// are skipped until their next_attempt_at. Must be called within a transaction;
+// concurrent callers skip locked rows. Returns nil if there is nothing to process.
+func (r *AvailabilityRepo) ClaimStarted(ctx context.Context) (*domain.Unavailability, error) {
+	query := `
+		SELECT a.unavailability_id, a.user_id, a.starts_at, a.ends_at, a.reason, a.created_at,
+		       a.reassign_attempts, a.reassign_error
+		FROM user_unavailability a
+		JOIN users u ON u.user_id = a.user_id
+		JOIN teams t ON t.team_name = u.team_name
+		WHERE a.processed_at IS NULL
+		  AND a.starts_at <= NOW()
+		  AND a.ends_at > NOW()
+		  AND (a.next_attempt_at IS NULL OR a.next_attempt_at <= NOW())
+		  AND t.auto_reassign_on_absence = true
+		ORDER BY a.starts_at
+		LIMIT 1
+		FOR UPDATE OF a SKIP LOCKED
+	`
+
+	period := &domain.Unavailability{}
+	err := conn(ctx, r.db).QueryRow(ctx, query).Scan(
+		&period.UnavailabilityID,
+		&period.UserID,
+		&period.StartsAt,
+		&period.EndsAt,
+		&period.Reason,
+		&period.CreatedAt,
+		&period.ReassignAttempts,
+		&period.ReassignError,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim unavailability: %w", err)
+	}
+
+	return period, nil
+}
+
+// MarkProcessed records that open reviews of the period's user were reassigned.
+func (r *AvailabilityRepo) MarkProcessed(ctx context.Context, unavailabilityID int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE user_unavailability
+		SET processed_at = NOW()
+		WHERE unavailability_id = $1
+	`, unavailabilityID)
+
+	if err != nil {
+		return fmt.Errorf("mark unavailability processed: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed reassignment attempt of the period and postpones
+// the next one until nextAttemptAt.
+func (r *AvailabilityRepo) MarkFailed(ctx context.Context, unavailabilityID int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE user_unavailability
+		SET reassign_attempts = reassign_attempts + 1,
+		    reassign_error = $2,
+		    next_attempt_at = $3
+		WHERE unavailability_id = $1
+	`, unavailabilityID, lastError, nextAttemptAt)
+
+	if err != nil {
+		return fmt.Errorf("mark unavailability failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdentityRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewIdentityRepo(db *pgxpool.Pool, logger *logger.Logger) *IdentityRepo {
+	return &IdentityRepo{
+		db:     db,
+		logger: logger.Component("repository/identity"),
+	}
+}
+
+// SaveIdentity maps a login to a user, replacing the previous mapping of the login.
+func (r *IdentityRepo) SaveIdentity(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error) {
+	saved := &domain.ExternalIdentity{
+		Provider: identity.Provider,
+		Login:    identity.Login,
+		UserID:   identity.UserID,
+	}
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO external_identities (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			created_at = NOW()
+		RETURNING created_at
+	`, string(identity.Provider), identity.Login, identity.UserID).Scan(&saved.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("upsert identity: %w", err)
+	}
+
+	return saved, nil
+}
+
+// ListIdentities retrieves mappings of the provider, or of every provider if it's empty.
+func (r *IdentityRepo) ListIdentities(ctx context.Context, provider domain.Provider) ([]*domain.ExternalIdentity, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT provider, login, user_id, created_at
+		FROM external_identities
+		WHERE $1 = '' OR provider = $1
+		ORDER BY provider, login
+	`, string(provider))
+	if err != nil {
+		return nil, fmt.Errorf("query identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []*domain.ExternalIdentity{}
+	for rows.Next() {
+		identity := &domain.ExternalIdentity{}
+		var provider string
+		if err := rows.Scan(&provider, &identity.Login, &identity.UserID, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan identity: %w", err)
+		}
+		identity.Provider = domain.Provider(provider)
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return identities, nil
+}
+
+// DeleteIdentity removes the mapping of a login.
+// Returns ErrIdentityNotFound if the login isn't mapped.
+func (r *IdentityRepo) DeleteIdentity(ctx context.Context, provider domain.Provider, login string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		DELETE FROM external_identities
+		WHERE provider = $1 AND login = $2
+	`, string(provider), login)
+	if err != nil {
+		return fmt.Errorf("delete identity: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrIdentityNotFound
+	}
+
+	return nil
+}
+
+// ResolveUser returns the user ID a login is mapped to.
+// Returns ErrIdentityNotFound if the login isn't mapped.
+func (r *IdentityRepo) ResolveUser(ctx context.Context, provider domain.Provider, login string) (string, error) {
+	var userID string
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT user_id
+		FROM external_identities
+		WHERE provider = $1 AND login = $2
+	`, string(provider), login).Scan(&userID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s login %q", domain.ErrIdentityNotFound, provider, login)
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve identity: %w", err)
+	}
+
+	return userID, nil
+}
+
+// LoginOf returns the login a user is known by on the provider; the latest mapping wins.
+// Returns ErrIdentityNotFound if the user has no login there.
+func (r *IdentityRepo) LoginOf(ctx context.Context, provider domain.Provider, userID string) (string, error) {
+	var login string
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT login
+		FROM external_identities
+		WHERE provider = $1 AND user_id = $2
+		ORDER BY created_at DESC, login
+		LIMIT 1
+	`, string(provider), userID).Scan(&login)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("%w: %s login of user %s", domain.ErrIdentityNotFound, provider, userID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("find login: %w", err)
+	}
+
+	return login, nil
+}
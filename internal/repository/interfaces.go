@@ -3,25 +3,104 @@ package repository
 import (
 	"context"
 	"github.com/ZertGraf/avito-test/internal/domain"
+	"time"
 )
 
 type TeamRepository interface {
 	TeamExists(ctx context.Context, teamName string) (bool, error)
 	CreateTeamWithMembers(ctx context.Context, team *domain.Team) (*domain.Team, error)
 	GetTeamWithMembers(ctx context.Context, teamName string) (*domain.Team, error)
+	GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error)
+	UpdateSettings(ctx context.Context, teamName string, settings *domain.TeamSettings) error
+	AddMember(ctx context.Context, teamName string, member domain.TeamMember) error
+	RemoveMember(ctx context.Context, teamName, userID string) error
+	MoveMember(ctx context.Context, userID, teamName string) error
+	HasOpenPRs(ctx context.Context, teamName string) (bool, error)
+	LockRoundRobinCursor(ctx context.Context, teamName, pool string) (string, error)
+	SetRoundRobinCursor(ctx context.Context, teamName, pool, userID string) error
+	DeleteTeam(ctx context.Context, teamName string) error
 }
 
 type UserRepository interface {
 	GetByID(ctx context.Context, userID string) (*domain.User, error)
-	SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error)
-	GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]*domain.User, error)
+	GetProfile(ctx context.Context, userID string) (*domain.UserProfile, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) (user *domain.User, changed bool, err error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error)
+	SetWorkingHours(ctx context.Context, userID, timezone, workStart, workEnd string) (*domain.User, error)
+	GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]*domain.ReviewerCandidate, error)
+	GetActiveCandidates(ctx context.Context, userIDs []string) ([]*domain.ReviewerCandidate, error)
 }
 
 type PRRepository interface {
-	Create(ctx context.Context, pr *domain.PullRequest) error
+	Create(ctx context.Context, pr *domain.PullRequest, actorID string) error
 	GetByID(ctx context.Context, prID string) (*domain.PullRequest, error)
-	Merge(ctx context.Context, prID string) error
-	GetByReviewer(ctx context.Context, userID string) ([]*domain.PullRequestShort, error)
-	ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error
+	Merge(ctx context.Context, prID, actorID string, from ...domain.PRStatus) error
+	Open(ctx context.Context, prID string, reviewers []domain.Reviewer, actorID string) error
+	Close(ctx context.Context, prID, actorID string) error
+	GetByReviewer(ctx context.Context, userID string, status domain.PRStatus, after *domain.Cursor, limit int) ([]*domain.PullRequestShort, error)
+	ReplaceReviewer(ctx context.Context, prID, oldUserID string, replacement domain.Reviewer, actorID string) error
+	RemoveReviewer(ctx context.Context, prID, reviewerID, actorID, reason string) error
+	SetDecision(ctx context.Context, prID, reviewerID string, decision domain.ReviewDecision) error
+	GetOverdue(ctx context.Context, teamName string, escalatingOnly bool) ([]*domain.OverdueReview, error)
+	GetEvents(ctx context.Context, prID string) ([]*domain.ReviewerEvent, error)
 	Exists(ctx context.Context, prID string) (bool, error)
+	Import(ctx context.Context, pr *domain.PullRequest, actorID string) error
+	Export(ctx context.Context, filter domain.PRFilter, fn func(*domain.PullRequest) error) error
+	List(ctx context.Context, filter domain.PRFilter, after *domain.Cursor, limit int) ([]*domain.PullRequest, error)
+}
+
+type OwnershipRepository interface {
+	CreateRule(ctx context.Context, rule *domain.OwnershipRule) (*domain.OwnershipRule, error)
+	ListRules(ctx context.Context, teamName string) ([]*domain.OwnershipRule, error)
+	DeleteRule(ctx context.Context, ruleID int64) error
+}
+
+type AvailabilityRepository interface {
+	Create(ctx context.Context, period *domain.Unavailability) (*domain.Unavailability, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.Unavailability, error)
+	ClaimStarted(ctx context.Context) (*domain.Unavailability, error)
+	MarkProcessed(ctx context.Context, unavailabilityID int64) error
+	MarkFailed(ctx context.Context, unavailabilityID int64, lastError string, nextAttemptAt time.Time) error
+}
+
+type StatsRepository interface {
+	UserStats(ctx context.Context, teamName string, window domain.StatsWindow) ([]*domain.UserStats, error)
+	TeamStats(ctx context.Context, window domain.StatsWindow) ([]*domain.TeamStats, error)
+	CycleTime(ctx context.Context, groupBy domain.CycleTimeGroup, window domain.StatsWindow) ([]*domain.CycleTimeStats, error)
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int64) error
+	Enqueue(ctx context.Context, eventType domain.EventType, payload []byte) (int64, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, deliveryID int64, responseStatus int) error
+	MarkFailed(ctx context.Context, deliveryID int64, responseStatus *int, lastError string, nextAttemptAt *time.Time) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+type OutboxRepository interface {
+	Append(ctx context.Context, eventType domain.EventType, payload []byte, occurredAt time.Time) error
+	TryLockRelay(ctx context.Context) (bool, error)
+	ListUnpublished(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, eventIDs []int64) error
+}
+
+type IdentityRepository interface {
+	SaveIdentity(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error)
+	ListIdentities(ctx context.Context, provider domain.Provider) ([]*domain.ExternalIdentity, error)
+	DeleteIdentity(ctx context.Context, provider domain.Provider, login string) error
+	ResolveUser(ctx context.Context, provider domain.Provider, login string) (string, error)
+	LoginOf(ctx context.Context, provider domain.Provider, userID string) (string, error)
+}
+
+type ReviewerSyncRepository interface {
+	Enqueue(ctx context.Context, task *domain.ReviewerSyncTask) error
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.ReviewerSyncTask, error)
+	Complete(ctx context.Context, taskID int64) error
+	Retry(ctx context.Context, taskID int64, lastError string, nextAttemptAt time.Time) error
+	DeadLetter(ctx context.Context, taskID int64, lastError string) error
+	ListDeadLetters(ctx context.Context, limit int) ([]*domain.ReviewerSyncTask, error)
+	Requeue(ctx context.Context, taskID int64) error
 }
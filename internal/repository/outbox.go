@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+// outboxRelayLock is the key of the advisory lock held by the outbox relay.
+const outboxRelayLock = "outbox_relay"
+
+type OutboxRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewOutboxRepo(db *pgxpool.Pool, logger *logger.Logger) *OutboxRepo {
+	return &OutboxRepo{
+		db:     db,
+		logger: logger.Component("repository/outbox"),
+	}
+}
+
+// Append stores an event in the outbox.
+// Joins the transaction bound to ctx, so the event is stored only if the change commits.
+func (r *OutboxRepo) Append(ctx context.Context, eventType domain.EventType, payload []byte, occurredAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO outbox (event_type, payload, occurred_at)
+		VALUES ($1, $2, $3)
+	`, string(eventType), payload, occurredAt)
+
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// TryLockRelay takes the relay lock for the rest of the transaction bound to ctx.
+// Returns false if another relay holds it.
+func (r *OutboxRepo) TryLockRelay(ctx context.Context) (bool, error) {
+	var locked bool
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT pg_try_advisory_xact_lock(hashtext($1))
+	`, outboxRelayLock).Scan(&locked)
+
+	if err != nil {
+		return false, fmt.Errorf("lock relay: %w", err)
+	}
+
+	return locked, nil
+}
+
+// ListUnpublished retrieves up to limit unpublished events, oldest first.
+// event_id is taken at insert, not at commit, so a transaction that is still running
+// may commit an event with a lower ID later. Events are listed only once every
+// transaction that started before their own has finished, so such late commits are
+// waited for instead of being published after newer events.
+func (r *OutboxRepo) ListUnpublished(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT event_id, event_type, payload, occurred_at
+		FROM outbox
+		WHERE published_at IS NULL
+		  AND xact_id < pg_snapshot_xmin(pg_current_snapshot())
+		ORDER BY event_id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.OutboxEvent{}
+	for rows.Next() {
+		var event domain.OutboxEvent
+		var eventType string
+		if err := rows.Scan(&event.EventID, &eventType, &event.Payload, &event.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		event.Type = domain.EventType(eventType)
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkPublished marks the events as published.
+func (r *OutboxRepo) MarkPublished(ctx context.Context, eventIDs []int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE outbox
+		SET published_at = NOW()
+		WHERE event_id = ANY($1)
+	`, eventIDs)
+
+	if err != nil {
+		return fmt.Errorf("mark published: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OwnershipRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewOwnershipRepo(db *pgxpool.Pool, logger *logger.Logger) *OwnershipRepo {
+	return &OwnershipRepo{
+		db:     db,
+		logger: logger.Component("repository/ownership"),
+	}
+}
+
+// CreateRule persists an ownership rule together with its owners.
+// Uses transaction to ensure atomicity.
+func (r *OwnershipRepo) CreateRule(ctx context.Context, rule *domain.OwnershipRule) (*domain.OwnershipRule, error) {
+	created := &domain.OwnershipRule{
+		TeamName: rule.TeamName,
+		Pattern:  rule.Pattern,
+		Owners:   rule.Owners,
+	}
+
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO code_owner_rules (team_name, pattern)
+			VALUES ($1, $2)
+			RETURNING rule_id, created_at
+		`, rule.TeamName, rule.Pattern).Scan(&created.RuleID, &created.CreatedAt)
+
+		if err != nil {
+			return fmt.Errorf("insert rule: %w", err)
+		}
+
+		for _, ownerID := range rule.Owners {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO code_owner_rule_owners (rule_id, user_id)
+				VALUES ($1, $2)
+			`, created.RuleID, ownerID)
+
+			if err != nil {
+				return fmt.Errorf("insert owner %s: %w", ownerID, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListRules retrieves all ownership rules of a team in creation order.
+// Returns empty slice if team has no rules.
+func (r *OwnershipRepo) ListRules(ctx context.Context, teamName string) ([]*domain.OwnershipRule, error) {
+	query := `
+		SELECT
+			c.rule_id,
+			c.team_name,
+			c.pattern,
+			c.created_at,
+			ARRAY(
+				SELECT o.user_id
+				FROM code_owner_rule_owners o
+				WHERE o.rule_id = c.rule_id
+				ORDER BY o.user_id
+			) AS owners
+		FROM code_owner_rules c
+		WHERE c.team_name = $1
+		ORDER BY c.rule_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.OwnershipRule{}
+	for rows.Next() {
+		rule := &domain.OwnershipRule{}
+		if err := rows.Scan(
+			&rule.RuleID,
+			&rule.TeamName,
+			&rule.Pattern,
+			&rule.CreatedAt,
+			&rule.Owners,
+		); err != nil {
+			return nil, fmt.Errorf("scan rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// DeleteRule removes an ownership rule and its owners.
+// Returns ErrRuleNotFound if rule doesn't exist.
+func (r *OwnershipRepo) DeleteRule(ctx context.Context, ruleID int64) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM code_owner_rules WHERE rule_id = $1`, ruleID)
+	if err != nil {
+		return fmt.Errorf("delete rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// withTx executes a function within a database transaction.
+// Joins the transaction bound to ctx by Transactor, if any.
+// Automatically handles commit/rollback based on error status.
+func (r *OwnershipRepo) withTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				r.logger.Error("failed to rollback transaction",
+					"error", rbErr,
+					"original_error", err,
+				)
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
+	"time"
 )
 
 type PRRepo struct {
@@ -21,9 +23,9 @@ func NewPRRepo(db *pgxpool.Pool, logger *logger.Logger) *PRRepo {
 	}
 }
 
-// Create persists a new pull request and its assigned reviewers.
+// Create persists a new pull request, its assigned reviewers and their ASSIGNED events.
 // Uses transaction to ensure atomicity.
-func (r *PRRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
+func (r *PRRepo) Create(ctx context.Context, pr *domain.PullRequest, actorID string) error {
 	return r.withTx(ctx, func(tx pgx.Tx) error {
 		// Insert PR record
 		_, err := tx.Exec(ctx, `
@@ -35,20 +37,35 @@ func (r *PRRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
 			return fmt.Errorf("insert pr: %w", err)
 		}
 
-		// Insert reviewer assignments
-		for _, reviewerID := range pr.AssignedReviewers {
-			_, err := tx.Exec(ctx, `
-                INSERT INTO pr_reviewers (pull_request_id, reviewer_id)
-                VALUES ($1, $2)
-            `, pr.PullRequestID, reviewerID)
+		return r.insertReviewers(ctx, tx, pr.PullRequestID, pr.Reviewers, actorID)
+	})
+}
 
-			if err != nil {
-				return fmt.Errorf("insert reviewer %s: %w", reviewerID, err)
-			}
+// insertReviewers inserts reviewer assignments and their ASSIGNED events within the given transaction.
+func (r *PRRepo) insertReviewers(ctx context.Context, tx pgx.Tx, prID string, reviewers []domain.Reviewer, actorID string) error {
+	for _, reviewer := range reviewers {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO pr_reviewers (pull_request_id, reviewer_id, source_team, reason, explanation)
+            VALUES ($1, $2, $3, $4, $5)
+        `, prID, reviewer.UserID, reviewer.TeamName, reviewer.Reason, reviewer.Explanation)
+
+		if err != nil {
+			return fmt.Errorf("insert reviewer %s: %w", reviewer.UserID, err)
 		}
 
-		return nil
-	})
+		err = r.insertEvent(ctx, tx, &domain.ReviewerEvent{
+			PullRequestID: prID,
+			EventType:     domain.ReviewerEventAssigned,
+			ReviewerID:    reviewer.UserID,
+			ActorID:       actorID,
+			Reason:        reviewer.Reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // GetByID retrieves a pull request with all assigned reviewers.
@@ -62,14 +79,21 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
             pr.status,
             pr.created_at,
             pr.merged_at,
-            COALESCE(r.reviewer_id, '') as reviewer_id
+            pr.closed_at,
+            COALESCE(r.reviewer_id, '') as reviewer_id,
+            COALESCE(r.source_team, '') as source_team,
+            COALESCE(r.decision, '') as decision,
+            r.assigned_at,
+            r.decided_at,
+            COALESCE(r.reason, '') as reason,
+            COALESCE(r.explanation, '') as explanation
         FROM pull_requests pr
         LEFT JOIN pr_reviewers r ON pr.pull_request_id = r.pull_request_id
         WHERE pr.pull_request_id = $1
         ORDER BY r.assigned_at
     `
 
-	rows, err := r.db.Query(ctx, query, prID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, prID)
 	if err != nil {
 		return nil, fmt.Errorf("query pr: %w", err)
 	}
@@ -77,7 +101,7 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 
 	var pr *domain.PullRequest
 	for rows.Next() {
-		var reviewerID string
+		var reviewer domain.Reviewer
 
 		// Initialize PR on first row
 		if pr == nil {
@@ -89,7 +113,14 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 				&pr.Status,
 				&pr.CreatedAt,
 				&pr.MergedAt,
-				&reviewerID,
+				&pr.ClosedAt,
+				&reviewer.UserID,
+				&reviewer.TeamName,
+				&reviewer.Decision,
+				&reviewer.AssignedAt,
+				&reviewer.DecidedAt,
+				&reviewer.Reason,
+				&reviewer.Explanation,
 			)
 		} else {
 			var tmpPR domain.PullRequest
@@ -102,7 +133,14 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 				&tmpPR.Status,
 				&tmpPR.CreatedAt,
 				&tmpPR.MergedAt,
-				&reviewerID,
+				&tmpPR.ClosedAt,
+				&reviewer.UserID,
+				&reviewer.TeamName,
+				&reviewer.Decision,
+				&reviewer.AssignedAt,
+				&reviewer.DecidedAt,
+				&reviewer.Reason,
+				&reviewer.Explanation,
 			)
 		}
 
@@ -110,9 +148,10 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
-		// Collect reviewer IDs
-		if reviewerID != "" {
-			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+		// Collect reviewers
+		if reviewer.UserID != "" {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+			pr.Reviewers = append(pr.Reviewers, reviewer)
 		}
 	}
 
@@ -124,50 +163,155 @@ func (r *PRRepo) GetByID(ctx context.Context, prID string) (*domain.PullRequest,
 		return nil, domain.ErrPRNotFound
 	}
 
-	// Ensure non-nil slice for consistency
+	// Ensure non-nil slices for consistency
 	if pr.AssignedReviewers == nil {
 		pr.AssignedReviewers = []string{}
+		pr.Reviewers = []domain.Reviewer{}
 	}
 
 	return pr, nil
 }
 
-// Merge marks a pull request as merged with current timestamp.
-func (r *PRRepo) Merge(ctx context.Context, prID string) error {
-	query := `
-        UPDATE pull_requests 
-        SET status = $1, merged_at = NOW()
-        WHERE pull_request_id = $2
-    `
-
-	result, err := r.db.Exec(ctx, query, domain.PRStatusMerged, prID)
-	if err != nil {
-		return fmt.Errorf("update pr: %w", err)
+// Merge marks a pull request in one of the from statuses as merged with current
+// timestamp and records MERGED_WITH events for its reviewers.
+// Returns ErrInvalidTransition if PR is missing or in another status.
+func (r *PRRepo) Merge(ctx context.Context, prID, actorID string, from ...domain.PRStatus) error {
+	statuses := make([]string, len(from))
+	for i, status := range from {
+		statuses[i] = string(status)
 	}
 
-	if result.RowsAffected() == 0 {
-		return domain.ErrPRNotFound
-	}
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            UPDATE pull_requests 
+            SET status = $1, merged_at = NOW(), closed_at = NULL
+            WHERE pull_request_id = $2
+              AND status = ANY($3)
+        `, domain.PRStatusMerged, prID, statuses)
 
-	return nil
+		if err != nil {
+			return fmt.Errorf("update pr: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrInvalidTransition
+		}
+
+		_, err = tx.Exec(ctx, `
+            INSERT INTO pr_reviewer_events (pull_request_id, event_type, reviewer_id, actor_id, reason)
+            SELECT pull_request_id, $2, reviewer_id, $3, $4
+            FROM pr_reviewers
+            WHERE pull_request_id = $1
+            ORDER BY assigned_at
+        `, prID, domain.ReviewerEventMergedWith, actorID, domain.ReasonMerged)
+
+		if err != nil {
+			return fmt.Errorf("insert merged events: %w", err)
+		}
+
+		return nil
+	})
 }
 
-// GetByReviewer retrieves all PRs assigned to a specific reviewer.
+// Open moves a draft or closed pull request to OPEN and assigns the given reviewers.
+// Pending reviews of kept reviewers count as assigned at reopening.
+// Returns ErrInvalidTransition if PR is missing or neither draft nor closed.
+func (r *PRRepo) Open(ctx context.Context, prID string, reviewers []domain.Reviewer, actorID string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            UPDATE pull_requests 
+            SET status = 'OPEN', closed_at = NULL
+            WHERE pull_request_id = $1
+              AND status IN ('DRAFT', 'CLOSED')
+        `, prID)
+
+		if err != nil {
+			return fmt.Errorf("update pr: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrInvalidTransition
+		}
+
+		// Review SLA of kept reviewers restarts with the PR
+		_, err = tx.Exec(ctx, `
+            UPDATE pr_reviewers
+            SET assigned_at = NOW()
+            WHERE pull_request_id = $1 AND decision = 'PENDING'
+        `, prID)
+
+		if err != nil {
+			return fmt.Errorf("restart review sla: %w", err)
+		}
+
+		return r.insertReviewers(ctx, tx, prID, reviewers, actorID)
+	})
+}
+
+// Close marks a draft or open pull request as closed without merging
+// and records CLOSED_WITH events for its reviewers.
+// Returns ErrInvalidTransition if PR is missing or neither draft nor open.
+func (r *PRRepo) Close(ctx context.Context, prID, actorID string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            UPDATE pull_requests 
+            SET status = 'CLOSED', closed_at = NOW()
+            WHERE pull_request_id = $1
+              AND status IN ('DRAFT', 'OPEN')
+        `, prID)
+
+		if err != nil {
+			return fmt.Errorf("update pr: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrInvalidTransition
+		}
+
+		_, err = tx.Exec(ctx, `
+            INSERT INTO pr_reviewer_events (pull_request_id, event_type, reviewer_id, actor_id, reason)
+            SELECT pull_request_id, $2, reviewer_id, $3, $4
+            FROM pr_reviewers
+            WHERE pull_request_id = $1
+            ORDER BY assigned_at
+        `, prID, domain.ReviewerEventClosedWith, actorID, domain.ReasonClosed)
+
+		if err != nil {
+			return fmt.Errorf("insert closed events: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByReviewer retrieves PRs assigned to a specific reviewer, newest first, starting after the cursor.
+// Empty status means any status, zero limit means no limit.
 // Returns empty slice if no PRs found.
-func (r *PRRepo) GetByReviewer(ctx context.Context, userID string) ([]*domain.PullRequestShort, error) {
+func (r *PRRepo) GetByReviewer(ctx context.Context, userID string, status domain.PRStatus, after *domain.Cursor, limit int) ([]*domain.PullRequestShort, error) {
+	var afterTime *time.Time
+	var afterID string
+	if after != nil {
+		afterTime = &after.Time
+		afterID = after.ID
+	}
+
 	query := `
 		SELECT 
 			pr.pull_request_id,
 			pr.pull_request_name,
 			pr.author_id,
-			pr.status
+			pr.status,
+			pr.created_at
 		FROM pull_requests pr
 		INNER JOIN pr_reviewers r ON pr.pull_request_id = r.pull_request_id
 		WHERE r.reviewer_id = $1
-		ORDER BY pr.created_at DESC
+		  AND ($2 = '' OR pr.status = $2)
+		  AND ($3::timestamptz IS NULL OR (pr.created_at, pr.pull_request_id) < ($3, $4))
+		ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+		LIMIT NULLIF($5, 0)
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, string(status), afterTime, afterID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("query prs: %w", err)
 	}
@@ -176,7 +320,7 @@ func (r *PRRepo) GetByReviewer(ctx context.Context, userID string) ([]*domain.Pu
 	var prs []*domain.PullRequestShort
 	for rows.Next() {
 		pr := &domain.PullRequestShort{}
-		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan pr: %w", err)
 		}
 		prs = append(prs, pr)
@@ -194,27 +338,239 @@ func (r *PRRepo) GetByReviewer(ctx context.Context, userID string) ([]*domain.Pu
 	return prs, nil
 }
 
-// ReplaceReviewer atomically replaces a reviewer on an open PR.
+// ReplaceReviewer atomically replaces a reviewer on an open PR and records a REASSIGNED event.
 // Ensures PR is still open and reviewer is assigned before replacement.
-func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
+func (r *PRRepo) ReplaceReviewer(ctx context.Context, prID, oldUserID string, replacement domain.Reviewer, actorID string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            UPDATE pr_reviewers
+            SET reviewer_id = $1, source_team = $2, decision = 'PENDING', decided_at = NULL,
+                reason = $3, explanation = $4, assigned_at = NOW()
+            WHERE pull_request_id = $5 
+              AND reviewer_id = $6
+              AND EXISTS (
+                  SELECT 1 FROM pull_requests 
+                  WHERE pull_request_id = $5 AND status = 'OPEN')
+        `, replacement.UserID, replacement.TeamName, replacement.Reason, replacement.Explanation, prID, oldUserID)
+
+		if err != nil {
+			return fmt.Errorf("replace reviewer: %w", err)
+		}
+
+		// No rows affected means either reviewer not assigned or PR not open
+		if result.RowsAffected() == 0 {
+			return domain.ErrNotAssigned
+		}
+
+		return r.insertEvent(ctx, tx, &domain.ReviewerEvent{
+			PullRequestID:      prID,
+			EventType:          domain.ReviewerEventReassigned,
+			ReviewerID:         replacement.UserID,
+			PreviousReviewerID: oldUserID,
+			ActorID:            actorID,
+			Reason:             replacement.Reason,
+		})
+	})
+}
+
+// RemoveReviewer unassigns a reviewer from an open PR and records an UNASSIGNED event.
+// Returns ErrNotAssigned if the user isn't a reviewer or PR is not open.
+func (r *PRRepo) RemoveReviewer(ctx context.Context, prID, reviewerID, actorID, reason string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            DELETE FROM pr_reviewers
+            WHERE pull_request_id = $1
+              AND reviewer_id = $2
+              AND EXISTS (
+                  SELECT 1 FROM pull_requests
+                  WHERE pull_request_id = $1 AND status = 'OPEN')
+        `, prID, reviewerID)
+
+		if err != nil {
+			return fmt.Errorf("remove reviewer: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrNotAssigned
+		}
+
+		return r.insertEvent(ctx, tx, &domain.ReviewerEvent{
+			PullRequestID: prID,
+			EventType:     domain.ReviewerEventUnassigned,
+			ReviewerID:    reviewerID,
+			ActorID:       actorID,
+			Reason:        reason,
+		})
+	})
+}
+
+// SetDecision records a reviewer's decision on an open PR and a REVIEWED event.
+// Returns ErrNotAssigned if the user isn't a reviewer or PR is not open.
+func (r *PRRepo) SetDecision(ctx context.Context, prID, reviewerID string, decision domain.ReviewDecision) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+            UPDATE pr_reviewers
+            SET decision = $1, decided_at = NOW()
+            WHERE pull_request_id = $2 
+              AND reviewer_id = $3
+              AND EXISTS (
+                  SELECT 1 FROM pull_requests 
+                  WHERE pull_request_id = $2 AND status = 'OPEN')
+        `, decision, prID, reviewerID)
+
+		if err != nil {
+			return fmt.Errorf("set decision: %w", err)
+		}
+
+		// No rows affected means either reviewer not assigned or PR not open
+		if result.RowsAffected() == 0 {
+			return domain.ErrNotAssigned
+		}
+
+		return r.insertEvent(ctx, tx, &domain.ReviewerEvent{
+			PullRequestID: prID,
+			EventType:     domain.ReviewerEventReviewed,
+			ReviewerID:    reviewerID,
+			ActorID:       reviewerID,
+			Reason:        string(decision),
+		})
+	})
+}
+
+// GetOverdue retrieves pending reviews of open PRs held longer than the review SLA
+// of the author's team, most overdue first. Empty teamName means all teams;
+// escalatingOnly limits the result to teams with escalate_overdue enabled.
+func (r *PRRepo) GetOverdue(ctx context.Context, teamName string, escalatingOnly bool) ([]*domain.OverdueReview, error) {
 	query := `
-        UPDATE pr_reviewers
-        SET reviewer_id = $1
-        WHERE pull_request_id = $2 
-          AND reviewer_id = $3
-          AND EXISTS (
-              SELECT 1 FROM pull_requests 
-              WHERE pull_request_id = $2 AND status = 'OPEN')
-    `
+		SELECT
+			pr.pull_request_id,
+			pr.pull_request_name,
+			pr.author_id,
+			t.team_name,
+			rv.reviewer_id,
+			rv.assigned_at,
+			rv.assigned_at + make_interval(hours => t.review_sla_hours) AS due_at
+		FROM pr_reviewers rv
+		JOIN pull_requests pr ON pr.pull_request_id = rv.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN teams t ON t.team_name = a.team_name
+		WHERE pr.status = 'OPEN'
+		  AND rv.decision = 'PENDING'
+		  AND t.review_sla_hours > 0
+		  AND rv.assigned_at + make_interval(hours => t.review_sla_hours) < NOW()
+		  AND ($1 = '' OR t.team_name = $1)
+		  AND (NOT $2 OR t.escalate_overdue)
+		ORDER BY due_at, pr.pull_request_id, rv.reviewer_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, teamName, escalatingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("query overdue reviews: %w", err)
+	}
+	defer rows.Close()
+
+	overdue := []*domain.OverdueReview{}
+	for rows.Next() {
+		review := &domain.OverdueReview{}
+		if err := rows.Scan(
+			&review.PullRequestID,
+			&review.PullRequestName,
+			&review.AuthorID,
+			&review.TeamName,
+			&review.ReviewerID,
+			&review.AssignedAt,
+			&review.DueAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan overdue review: %w", err)
+		}
+		overdue = append(overdue, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return overdue, nil
+}
 
-	result, err := r.db.Exec(ctx, query, newUserID, prID, oldUserID)
+// GetEvents retrieves reviewer history of a pull request in chronological order.
+// Returns empty slice if PR has no events.
+func (r *PRRepo) GetEvents(ctx context.Context, prID string) ([]*domain.ReviewerEvent, error) {
+	query := `
+		SELECT
+			event_id,
+			pull_request_id,
+			event_type,
+			reviewer_id,
+			COALESCE(previous_reviewer_id, '') AS previous_reviewer_id,
+			actor_id,
+			reason,
+			created_at
+		FROM pr_reviewer_events
+		WHERE pull_request_id = $1
+		ORDER BY event_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, prID)
 	if err != nil {
-		return fmt.Errorf("replace reviewer: %w", err)
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.ReviewerEvent{}
+	for rows.Next() {
+		event := &domain.ReviewerEvent{}
+		if err := rows.Scan(
+			&event.EventID,
+			&event.PullRequestID,
+			&event.EventType,
+			&event.ReviewerID,
+			&event.PreviousReviewerID,
+			&event.ActorID,
+			&event.Reason,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// insertEvent appends a reviewer event within the given transaction.
+// Zero CreatedAt means the event happens now.
+func (r *PRRepo) insertEvent(ctx context.Context, tx pgx.Tx, event *domain.ReviewerEvent) error {
+	var previousReviewerID *string
+	if event.PreviousReviewerID != "" {
+		previousReviewerID = &event.PreviousReviewerID
 	}
 
-	// No rows affected means either reviewer not assigned or PR not open
-	if result.RowsAffected() == 0 {
-		return domain.ErrNotAssigned
+	var createdAt *time.Time
+	if !event.CreatedAt.IsZero() {
+		createdAt = &event.CreatedAt
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO pr_reviewer_events
+			(pull_request_id, event_type, reviewer_id, previous_reviewer_id, actor_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()))
+	`,
+		event.PullRequestID,
+		event.EventType,
+		event.ReviewerID,
+		previousReviewerID,
+		event.ActorID,
+		event.Reason,
+		createdAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("insert %s event: %w", event.EventType, err)
 	}
 
 	return nil
@@ -225,7 +581,7 @@ func (r *PRRepo) Exists(ctx context.Context, prID string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
 
-	err := r.db.QueryRow(ctx, query, prID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, prID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check pr exists: %w", err)
 	}
@@ -233,9 +589,321 @@ func (r *PRRepo) Exists(ctx context.Context, prID string) (bool, error) {
 	return exists, nil
 }
 
+// Import persists a pull request as is: status, timestamps and reviewers with their decisions
+// are taken from pr. Missing created_at and assigned_at default to now.
+// Reviewers get ASSIGNED events with their reasons.
+func (r *PRRepo) Import(ctx context.Context, pr *domain.PullRequest, actorID string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+            INSERT INTO pull_requests
+                (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at)
+            VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6, $7)
+        `, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt, pr.ClosedAt)
+
+		if err != nil {
+			return fmt.Errorf("insert pr: %w", err)
+		}
+
+		for _, reviewer := range pr.Reviewers {
+			_, err := tx.Exec(ctx, `
+                INSERT INTO pr_reviewers
+                    (pull_request_id, reviewer_id, source_team, decision, assigned_at, decided_at, reason, explanation)
+                VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6, $7, $8)
+            `,
+				pr.PullRequestID,
+				reviewer.UserID,
+				reviewer.TeamName,
+				reviewer.Decision,
+				reviewer.AssignedAt,
+				reviewer.DecidedAt,
+				reviewer.Reason,
+				reviewer.Explanation,
+			)
+
+			if err != nil {
+				return fmt.Errorf("insert reviewer %s: %w", reviewer.UserID, err)
+			}
+
+			event := &domain.ReviewerEvent{
+				PullRequestID: pr.PullRequestID,
+				EventType:     domain.ReviewerEventAssigned,
+				ReviewerID:    reviewer.UserID,
+				ActorID:       actorID,
+				Reason:        reviewer.Reason,
+			}
+			// History keeps the original assignment time
+			if reviewer.AssignedAt != nil {
+				event.CreatedAt = *reviewer.AssignedAt
+			}
+
+			err = r.insertEvent(ctx, tx, event)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Export streams pull requests matching the filter, with their reviewers, to fn
+// ordered by creation time. Stops at the first error returned by fn.
+func (r *PRRepo) Export(ctx context.Context, filter domain.PRFilter, fn func(*domain.PullRequest) error) error {
+	query := `
+        SELECT
+            pr.pull_request_id,
+            pr.pull_request_name,
+            pr.author_id,
+            pr.status,
+            pr.created_at,
+            pr.merged_at,
+            pr.closed_at,
+            COALESCE(r.reviewer_id, '') as reviewer_id,
+            COALESCE(r.source_team, '') as source_team,
+            COALESCE(r.decision, '') as decision,
+            r.assigned_at,
+            r.decided_at,
+            COALESCE(r.reason, '') as reason,
+            COALESCE(r.explanation, '') as explanation
+        FROM pull_requests pr
+        LEFT JOIN pr_reviewers r ON pr.pull_request_id = r.pull_request_id
+        WHERE ` + prFilterCondition + `
+        ORDER BY pr.created_at, pr.pull_request_id, r.assigned_at
+    `
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, prFilterArgs(filter)...)
+	if err != nil {
+		return fmt.Errorf("query prs: %w", err)
+	}
+	defer rows.Close()
+
+	var pr *domain.PullRequest
+	for rows.Next() {
+		var row domain.PullRequest
+		var reviewer domain.Reviewer
+
+		err := rows.Scan(
+			&row.PullRequestID,
+			&row.PullRequestName,
+			&row.AuthorID,
+			&row.Status,
+			&row.CreatedAt,
+			&row.MergedAt,
+			&row.ClosedAt,
+			&reviewer.UserID,
+			&reviewer.TeamName,
+			&reviewer.Decision,
+			&reviewer.AssignedAt,
+			&reviewer.DecidedAt,
+			&reviewer.Reason,
+			&reviewer.Explanation,
+		)
+		if err != nil {
+			return fmt.Errorf("scan row: %w", err)
+		}
+
+		// Rows of one PR are adjacent; emit the previous PR once a new one starts
+		if pr == nil || pr.PullRequestID != row.PullRequestID {
+			if pr != nil {
+				if err := fn(pr); err != nil {
+					return err
+				}
+			}
+
+			pr = &row
+			pr.AssignedReviewers = []string{}
+			pr.Reviewers = []domain.Reviewer{}
+		}
+
+		if reviewer.UserID != "" {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+			pr.Reviewers = append(pr.Reviewers, reviewer)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	if pr != nil {
+		return fn(pr)
+	}
+
+	return nil
+}
+
+// prFilterCondition matches pull requests "pr" against PRFilter bound by prFilterArgs to $1-$9.
+const prFilterCondition = `
+            ($1 = '' OR pr.status = $1)
+            AND ($2 = '' OR pr.author_id IN (SELECT user_id FROM users WHERE team_name = $2))
+            AND ($3 = '' OR pr.author_id = $3)
+            AND ($4 = '' OR EXISTS (
+                SELECT 1 FROM pr_reviewers fr
+                WHERE fr.pull_request_id = pr.pull_request_id AND fr.reviewer_id = $4))
+            AND ($5 = '' OR lower(pr.pull_request_name) LIKE lower($5) || '%')
+            AND ($6::timestamptz IS NULL OR pr.created_at >= $6)
+            AND ($7::timestamptz IS NULL OR pr.created_at < $7)
+            AND ($8::timestamptz IS NULL OR pr.merged_at >= $8)
+            AND ($9::timestamptz IS NULL OR pr.merged_at < $9)`
+
+// prFilterArgs returns arguments for prFilterCondition.
+func prFilterArgs(filter domain.PRFilter) []any {
+	return []any{
+		string(filter.Status),
+		filter.TeamName,
+		filter.AuthorID,
+		filter.ReviewerID,
+		escapeLike(filter.Query),
+		filter.CreatedFrom,
+		filter.CreatedTo,
+		filter.MergedFrom,
+		filter.MergedTo,
+	}
+}
+
+// escapeLike escapes LIKE wildcards so the value is matched literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// List retrieves a page of pull requests matching the filter, newest first,
+// starting after the cursor. Reviewers are loaded with a second query.
+func (r *PRRepo) List(ctx context.Context, filter domain.PRFilter, after *domain.Cursor, limit int) ([]*domain.PullRequest, error) {
+	var afterTime *time.Time
+	var afterID string
+	if after != nil {
+		afterTime = &after.Time
+		afterID = after.ID
+	}
+
+	// $10, $11 - keyset position, $12 - page size
+	query := `
+        SELECT
+            pr.pull_request_id,
+            pr.pull_request_name,
+            pr.author_id,
+            pr.status,
+            pr.created_at,
+            pr.merged_at,
+            pr.closed_at
+        FROM pull_requests pr
+        WHERE ` + prFilterCondition + `
+          AND ($10::timestamptz IS NULL OR (pr.created_at, pr.pull_request_id) < ($10, $11))
+        ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+        LIMIT $12
+    `
+
+	args := append(prFilterArgs(filter), afterTime, afterID, limit)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query prs: %w", err)
+	}
+	defer rows.Close()
+
+	prs := []*domain.PullRequest{}
+	byID := make(map[string]*domain.PullRequest)
+	for rows.Next() {
+		pr := &domain.PullRequest{
+			AssignedReviewers: []string{},
+			Reviewers:         []domain.Reviewer{},
+		}
+		err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.Status,
+			&pr.CreatedAt,
+			&pr.MergedAt,
+			&pr.ClosedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan pr: %w", err)
+		}
+		prs = append(prs, pr)
+		byID[pr.PullRequestID] = pr
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	if len(prs) == 0 {
+		return prs, nil
+	}
+
+	if err := r.loadReviewers(ctx, byID); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// loadReviewers fills reviewers of the given pull requests keyed by ID.
+func (r *PRRepo) loadReviewers(ctx context.Context, prs map[string]*domain.PullRequest) error {
+	ids := make([]string, 0, len(prs))
+	for id := range prs {
+		ids = append(ids, id)
+	}
+
+	query := `
+        SELECT
+            pull_request_id,
+            reviewer_id,
+            COALESCE(source_team, '') as source_team,
+            decision,
+            assigned_at,
+            decided_at,
+            reason,
+            explanation
+        FROM pr_reviewers
+        WHERE pull_request_id = ANY($1)
+        ORDER BY assigned_at
+    `
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("query reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID string
+		var reviewer domain.Reviewer
+		err := rows.Scan(
+			&prID,
+			&reviewer.UserID,
+			&reviewer.TeamName,
+			&reviewer.Decision,
+			&reviewer.AssignedAt,
+			&reviewer.DecidedAt,
+			&reviewer.Reason,
+			&reviewer.Explanation,
+		)
+		if err != nil {
+			return fmt.Errorf("scan reviewer: %w", err)
+		}
+
+		pr := prs[prID]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return nil
+}
+
 // withTx executes a function within a database transaction.
+// Joins the transaction bound to ctx by Transactor, if any.
 // Automatically handles commit/rollback based on error status.
 func (r *PRRepo) withTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
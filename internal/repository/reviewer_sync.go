@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type ReviewerSyncRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewReviewerSyncRepo(db *pgxpool.Pool, logger *logger.Logger) *ReviewerSyncRepo {
+	return &ReviewerSyncRepo{
+		db:     db,
+		logger: logger.Component("repository/reviewer_sync"),
+	}
+}
+
+// Enqueue creates a pending sync task. Joins the transaction bound to ctx.
+func (r *ReviewerSyncRepo) Enqueue(ctx context.Context, task *domain.ReviewerSyncTask) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO reviewer_sync_tasks (pull_request_id, add_reviewer_id, remove_reviewer_id)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+	`, task.PullRequestID, task.AddReviewerID, task.RemoveReviewerID)
+
+	if err != nil {
+		return fmt.Errorf("insert sync task: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue takes up to limit due tasks, counts the attempt and postpones the next one by lease,
+// so a crashed worker's tasks are retried later. Only the oldest task of a pull request is
+// taken, so changes reach the git hosting in the order they were made.
+// Concurrent callers skip each other's rows.
+func (r *ReviewerSyncRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.ReviewerSyncTask, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		UPDATE reviewer_sync_tasks t
+		SET attempts = t.attempts + 1,
+		    next_attempt_at = NOW() + $2::interval
+		WHERE t.task_id IN (
+			SELECT c.task_id
+			FROM reviewer_sync_tasks c
+			WHERE c.next_attempt_at <= NOW()
+			  AND NOT EXISTS (
+				  SELECT 1
+				  FROM reviewer_sync_tasks o
+				  WHERE o.pull_request_id = c.pull_request_id AND o.task_id < c.task_id)
+			ORDER BY c.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF c SKIP LOCKED)
+		RETURNING t.task_id, t.pull_request_id, COALESCE(t.add_reviewer_id, ''), COALESCE(t.remove_reviewer_id, ''),
+			t.attempts, t.next_attempt_at, t.last_error, t.created_at
+	`, limit, lease)
+	if err != nil {
+		return nil, fmt.Errorf("claim sync tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSyncTasks(rows, false)
+}
+
+// Complete removes a task synced successfully.
+func (r *ReviewerSyncRepo) Complete(ctx context.Context, taskID int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM reviewer_sync_tasks WHERE task_id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("complete sync task: %w", err)
+	}
+
+	return nil
+}
+
+// Retry records a failed attempt and schedules the next one.
+func (r *ReviewerSyncRepo) Retry(ctx context.Context, taskID int64, lastError string, nextAttemptAt time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE reviewer_sync_tasks
+		SET last_error = $2, next_attempt_at = $3
+		WHERE task_id = $1
+	`, taskID, lastError, nextAttemptAt)
+
+	if err != nil {
+		return fmt.Errorf("retry sync task: %w", err)
+	}
+
+	return nil
+}
+
+// DeadLetter moves a task that failed for good to the dead letters.
+func (r *ReviewerSyncRepo) DeadLetter(ctx context.Context, taskID int64, lastError string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		WITH failed AS (
+			DELETE FROM reviewer_sync_tasks
+			WHERE task_id = $1
+			RETURNING task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, attempts, created_at
+		)
+		INSERT INTO reviewer_sync_dead_letters
+			(task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, attempts, last_error, created_at)
+		SELECT task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, attempts, $2, created_at
+		FROM failed
+	`, taskID, lastError)
+
+	if err != nil {
+		return fmt.Errorf("dead letter sync task: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeadLetters retrieves up to limit dead letters, latest failures first.
+func (r *ReviewerSyncRepo) ListDeadLetters(ctx context.Context, limit int) ([]*domain.ReviewerSyncTask, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT task_id, pull_request_id, COALESCE(add_reviewer_id, ''), COALESCE(remove_reviewer_id, ''),
+			attempts, NULL::timestamptz, last_error, created_at, failed_at
+		FROM reviewer_sync_dead_letters
+		ORDER BY failed_at DESC, task_id DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSyncTasks(rows, true)
+}
+
+// Requeue moves a dead letter back to the pending tasks with attempts reset.
+// The task keeps its ID and so its place among other tasks of the pull request.
+// Returns ErrSyncTaskNotFound if there's no such dead letter.
+func (r *ReviewerSyncRepo) Requeue(ctx context.Context, taskID int64) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		WITH revived AS (
+			DELETE FROM reviewer_sync_dead_letters
+			WHERE task_id = $1
+			RETURNING task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, last_error, created_at
+		)
+		INSERT INTO reviewer_sync_tasks
+			(task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, last_error, created_at)
+		SELECT task_id, pull_request_id, add_reviewer_id, remove_reviewer_id, last_error, created_at
+		FROM revived
+	`, taskID)
+	if err != nil {
+		return fmt.Errorf("requeue sync task: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSyncTaskNotFound
+	}
+
+	return nil
+}
+
+// scanSyncTasks scans task rows, followed by failed_at if withFailedAt is set.
+func scanSyncTasks(rows pgx.Rows, withFailedAt bool) ([]*domain.ReviewerSyncTask, error) {
+	tasks := []*domain.ReviewerSyncTask{}
+	for rows.Next() {
+		task := &domain.ReviewerSyncTask{}
+		dest := []any{
+			&task.TaskID,
+			&task.PullRequestID,
+			&task.AddReviewerID,
+			&task.RemoveReviewerID,
+			&task.Attempts,
+			&task.NextAttemptAt,
+			&task.LastError,
+			&task.CreatedAt,
+		}
+		if withFailedAt {
+			dest = append(dest, &task.FailedAt)
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan sync task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewStatsRepo(db *pgxpool.Pool, logger *logger.Logger) *StatsRepo {
+	return &StatsRepo{
+		db:     db,
+		logger: logger.Component("repository/stats"),
+	}
+}
+
+// UserStats aggregates review activity per user within the window.
+// Assignments are counted from the history, so reviews reassigned away still count.
+// Empty teamName means all users.
+func (r *StatsRepo) UserStats(ctx context.Context, teamName string, window domain.StatsWindow) ([]*domain.UserStats, error) {
+	// $1, $2 - window bounds, NULL means open bound
+	query := `
+		WITH assigned AS (
+			SELECT reviewer_id AS user_id, COUNT(*) AS n
+			FROM pr_reviewer_events
+			WHERE event_type IN ('ASSIGNED', 'REASSIGNED')
+			  AND ($1::timestamptz IS NULL OR created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR created_at < $2)
+			GROUP BY reviewer_id
+		), open_reviews AS (
+			SELECT r.reviewer_id AS user_id, COUNT(*) AS n
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			WHERE pr.status = 'OPEN'
+			GROUP BY r.reviewer_id
+		), merged_reviewed AS (
+			SELECT r.reviewer_id AS user_id, COUNT(*) AS n
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			WHERE pr.status = 'MERGED'
+			  AND ($1::timestamptz IS NULL OR pr.merged_at >= $1)
+			  AND ($2::timestamptz IS NULL OR pr.merged_at < $2)
+			GROUP BY r.reviewer_id
+		), merged_authored AS (
+			SELECT author_id AS user_id, COUNT(*) AS n
+			FROM pull_requests
+			WHERE status = 'MERGED'
+			  AND ($1::timestamptz IS NULL OR merged_at >= $1)
+			  AND ($2::timestamptz IS NULL OR merged_at < $2)
+			GROUP BY author_id
+		), reassignments AS (
+			SELECT
+				u.user_id,
+				COUNT(*) FILTER (WHERE u.user_id = e.reviewer_id) AS received,
+				COUNT(*) FILTER (WHERE u.user_id = e.previous_reviewer_id) AS given
+			FROM pr_reviewer_events e
+			JOIN users u ON u.user_id IN (e.reviewer_id, e.previous_reviewer_id)
+			WHERE e.event_type = 'REASSIGNED'
+			  AND ($1::timestamptz IS NULL OR e.created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR e.created_at < $2)
+			GROUP BY u.user_id
+		)
+		SELECT
+			u.user_id,
+			u.username,
+			COALESCE(u.team_name, '') AS team_name,
+			u.is_active,
+			COALESCE(a.n, 0) AS assigned,
+			COALESCE(o.n, 0) AS open_reviews,
+			COALESCE(mr.n, 0) AS merged_reviewed,
+			COALESCE(ma.n, 0) AS merged_authored,
+			COALESCE(ra.received, 0) AS reassignments_received,
+			COALESCE(ra.given, 0) AS reassignments_given
+		FROM users u
+		LEFT JOIN assigned a ON a.user_id = u.user_id
+		LEFT JOIN open_reviews o ON o.user_id = u.user_id
+		LEFT JOIN merged_reviewed mr ON mr.user_id = u.user_id
+		LEFT JOIN merged_authored ma ON ma.user_id = u.user_id
+		LEFT JOIN reassignments ra ON ra.user_id = u.user_id
+		WHERE ($3 = '' OR u.team_name = $3)
+		ORDER BY u.user_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, window.From, window.To, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("query user stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []*domain.UserStats{}
+	for rows.Next() {
+		s := &domain.UserStats{}
+		if err := rows.Scan(
+			&s.UserID,
+			&s.Username,
+			&s.TeamName,
+			&s.IsActive,
+			&s.Assigned,
+			&s.OpenReviews,
+			&s.MergedReviewed,
+			&s.MergedAuthored,
+			&s.ReassignmentsReceived,
+			&s.ReassignmentsGiven,
+		); err != nil {
+			return nil, fmt.Errorf("scan user stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// TeamStats aggregates review activity per team within the window.
+// Activity is attributed to teams by current membership of authors and reviewers.
+func (r *StatsRepo) TeamStats(ctx context.Context, window domain.StatsWindow) ([]*domain.TeamStats, error) {
+	// $1, $2 - window bounds, NULL means open bound
+	query := `
+		WITH members AS (
+			SELECT
+				team_name,
+				COUNT(*) AS members,
+				COUNT(*) FILTER (WHERE is_active) AS active_members
+			FROM users
+			WHERE team_name IS NOT NULL
+			GROUP BY team_name
+		), authored AS (
+			SELECT
+				u.team_name,
+				COUNT(*) FILTER (WHERE ($1::timestamptz IS NULL OR pr.created_at >= $1)
+				                   AND ($2::timestamptz IS NULL OR pr.created_at < $2)) AS created,
+				COUNT(*) FILTER (WHERE pr.status = 'MERGED'
+				                   AND ($1::timestamptz IS NULL OR pr.merged_at >= $1)
+				                   AND ($2::timestamptz IS NULL OR pr.merged_at < $2)) AS merged,
+				COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			GROUP BY u.team_name
+		), assigned AS (
+			SELECT u.team_name, COUNT(*) AS n
+			FROM pr_reviewer_events e
+			JOIN users u ON u.user_id = e.reviewer_id
+			WHERE e.event_type IN ('ASSIGNED', 'REASSIGNED')
+			  AND ($1::timestamptz IS NULL OR e.created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR e.created_at < $2)
+			GROUP BY u.team_name
+		), open_reviews AS (
+			SELECT u.team_name, COUNT(*) AS n
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			JOIN users u ON u.user_id = r.reviewer_id
+			WHERE pr.status = 'OPEN'
+			GROUP BY u.team_name
+		), reassignments AS (
+			SELECT u.team_name, COUNT(*) AS n
+			FROM pr_reviewer_events e
+			JOIN users u ON u.user_id = e.reviewer_id
+			WHERE e.event_type = 'REASSIGNED'
+			  AND ($1::timestamptz IS NULL OR e.created_at >= $1)
+			  AND ($2::timestamptz IS NULL OR e.created_at < $2)
+			GROUP BY u.team_name
+		)
+		SELECT
+			t.team_name,
+			COALESCE(m.members, 0),
+			COALESCE(m.active_members, 0),
+			COALESCE(a.created, 0),
+			COALESCE(a.merged, 0),
+			COALESCE(a.open, 0),
+			COALESCE(ag.n, 0),
+			COALESCE(o.n, 0),
+			COALESCE(ra.n, 0)
+		FROM teams t
+		LEFT JOIN members m ON m.team_name = t.team_name
+		LEFT JOIN authored a ON a.team_name = t.team_name
+		LEFT JOIN assigned ag ON ag.team_name = t.team_name
+		LEFT JOIN open_reviews o ON o.team_name = t.team_name
+		LEFT JOIN reassignments ra ON ra.team_name = t.team_name
+		ORDER BY t.team_name
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, window.From, window.To)
+	if err != nil {
+		return nil, fmt.Errorf("query team stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []*domain.TeamStats{}
+	for rows.Next() {
+		s := &domain.TeamStats{}
+		if err := rows.Scan(
+			&s.TeamName,
+			&s.Members,
+			&s.ActiveMembers,
+			&s.PRsCreated,
+			&s.PRsMerged,
+			&s.OpenPRs,
+			&s.Assigned,
+			&s.OpenReviews,
+			&s.Reassignments,
+		); err != nil {
+			return nil, fmt.Errorf("scan team stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// cycleTimeKeys maps groupings to the columns of the merged and reviews CTEs
+// that identify a group in CycleTime.
+var cycleTimeKeys = map[domain.CycleTimeGroup]struct{ prs, reviews string }{
+	domain.CycleTimeByTeam:     {prs: "team_name", reviews: "team_name"},
+	domain.CycleTimeByAuthor:   {prs: "author_id", reviews: "author_id"},
+	domain.CycleTimeByReviewer: {prs: "", reviews: "reviewer_id"},
+}
+
+// CycleTime computes p50/p90/p99 of creation-to-merge and assignment-to-merge
+// times of PRs merged within the window, grouped by team, author or reviewer.
+// Review time runs from the first assignment on the PR, so reopening or
+// reassigning doesn't restart it.
+func (r *StatsRepo) CycleTime(ctx context.Context, groupBy domain.CycleTimeGroup, window domain.StatsWindow) ([]*domain.CycleTimeStats, error) {
+	keys, ok := cycleTimeKeys[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown cycle time grouping %q", groupBy)
+	}
+
+	// Reviewers see cycle time of every PR they reviewed, each PR counted once per reviewer
+	cycleSource := fmt.Sprintf("SELECT %s AS group_key, cycle_time FROM merged", keys.prs)
+	if keys.prs == "" {
+		cycleSource = fmt.Sprintf("SELECT %s AS group_key, cycle_time FROM reviews", keys.reviews)
+	}
+
+	// $1, $2 - window bounds on merged_at, NULL means open bound
+	query := fmt.Sprintf(`
+		WITH merged AS (
+			SELECT
+				pr.pull_request_id,
+				pr.author_id,
+				COALESCE(u.team_name, '') AS team_name,
+				EXTRACT(EPOCH FROM pr.merged_at - pr.created_at)::double precision AS cycle_time,
+				pr.merged_at
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			WHERE pr.status = 'MERGED'
+			  AND ($1::timestamptz IS NULL OR pr.merged_at >= $1)
+			  AND ($2::timestamptz IS NULL OR pr.merged_at < $2)
+		), first_assigned AS (
+			SELECT e.pull_request_id, MIN(e.created_at) AS assigned_at
+			FROM pr_reviewer_events e
+			JOIN merged m ON m.pull_request_id = e.pull_request_id
+			WHERE e.event_type = 'ASSIGNED'
+			GROUP BY e.pull_request_id
+		), reviews AS (
+			SELECT
+				m.pull_request_id,
+				m.author_id,
+				m.team_name,
+				r.reviewer_id,
+				m.cycle_time,
+				EXTRACT(EPOCH FROM m.merged_at - COALESCE(f.assigned_at, r.assigned_at))::double precision AS review_time
+			FROM merged m
+			JOIN pr_reviewers r ON r.pull_request_id = m.pull_request_id
+			LEFT JOIN first_assigned f ON f.pull_request_id = m.pull_request_id
+		), cycle AS (
+			SELECT
+				group_key,
+				COUNT(*) AS prs,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY cycle_time) AS p50,
+				percentile_cont(0.9) WITHIN GROUP (ORDER BY cycle_time) AS p90,
+				percentile_cont(0.99) WITHIN GROUP (ORDER BY cycle_time) AS p99
+			FROM (%s) source
+			GROUP BY group_key
+		), review AS (
+			SELECT
+				%s AS group_key,
+				COUNT(*) AS reviews,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY review_time) AS p50,
+				percentile_cont(0.9) WITHIN GROUP (ORDER BY review_time) AS p90,
+				percentile_cont(0.99) WITHIN GROUP (ORDER BY review_time) AS p99
+			FROM reviews
+			GROUP BY 1
+		)
+		SELECT
+			COALESCE(c.group_key, rv.group_key) AS group_key,
+			COALESCE(c.prs, 0),
+			c.p50, c.p90, c.p99,
+			COALESCE(rv.reviews, 0),
+			rv.p50, rv.p90, rv.p99
+		FROM cycle c
+		FULL JOIN review rv ON rv.group_key = c.group_key
+		ORDER BY 1
+	`, cycleSource, keys.reviews)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, window.From, window.To)
+	if err != nil {
+		return nil, fmt.Errorf("query cycle time: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []*domain.CycleTimeStats{}
+	for rows.Next() {
+		s := &domain.CycleTimeStats{}
+		if err := rows.Scan(
+			&s.Group,
+			&s.PRs,
+			&s.CycleTime.P50,
+			&s.CycleTime.P90,
+			&s.CycleTime.P99,
+			&s.Reviews,
+			&s.ReviewTime.P50,
+			&s.ReviewTime.P90,
+			&s.ReviewTime.P99,
+		); err != nil {
+			return nil, fmt.Errorf("scan cycle time: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/tern/v2/migrate"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestDB migrates a fresh schema in the database at TEST_DATABASE_URL
+// and drops it when the test ends. Tests are skipped without the variable.
+func newTestDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse database url: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := db.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+		db.Close()
+	})
+
+	if _, err := db.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire connection: %v", err)
+	}
+	defer conn.Release()
+
+	migrator, err := migrate.NewMigrator(ctx, conn.Conn(), "schema_version")
+	if err != nil {
+		t.Fatalf("create migrator: %v", err)
+	}
+	if err := migrator.LoadMigrations(migrations.MigrationFiles); err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if err := migrator.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+func newTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+
+	log, err := logger.New(&logger.Config{Level: "error", Format: "json"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return log
+}
+
+func TestCycleTimeFromFirstAssignment(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	created := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	// u2 is assigned an hour after creation and replaced by u3 four hours later;
+	// the PR is merged ten hours after creation.
+	seed := []struct {
+		query string
+		args  []any
+	}{
+		{`INSERT INTO teams (team_name) VALUES ('backend')`, nil},
+		{`INSERT INTO users (user_id, username, team_name) VALUES ('u1', 'Alice', 'backend'), ('u2', 'Bob', 'backend'), ('u3', 'Carol', 'backend')`, nil},
+		{`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
+		  VALUES ('pr-1', 'Add search', 'u1', 'MERGED', $1, $2)`, []any{created, created.Add(10 * time.Hour)}},
+		{`INSERT INTO pr_reviewers (pull_request_id, reviewer_id, assigned_at) VALUES ('pr-1', 'u3', $1)`, []any{created.Add(5 * time.Hour)}},
+		{`INSERT INTO pr_reviewer_events (pull_request_id, event_type, reviewer_id, previous_reviewer_id, actor_id, created_at)
+		  VALUES ('pr-1', 'ASSIGNED', 'u2', NULL, 'system', $1), ('pr-1', 'REASSIGNED', 'u3', 'u2', 'u1', $2)`,
+			[]any{created.Add(time.Hour), created.Add(5 * time.Hour)}},
+	}
+	for _, s := range seed {
+		if _, err := db.Exec(ctx, s.query, s.args...); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	repo := NewStatsRepo(db, newTestLogger(t))
+
+	stats, err := repo.CycleTime(ctx, domain.CycleTimeByReviewer, domain.StatsWindow{})
+	if err != nil {
+		t.Fatalf("CycleTime() error = %v", err)
+	}
+	if len(stats) != 1 || stats[0].Group != "u3" {
+		t.Fatalf("CycleTime() = %+v, want a single group u3", stats)
+	}
+
+	want := (9 * time.Hour).Seconds()
+	got := stats[0].ReviewTime.P50
+	if got == nil {
+		t.Fatal("review time p50 is null")
+	}
+	if *got != want {
+		t.Errorf("review time p50 = %v, want %v", *got, want)
+	}
+}
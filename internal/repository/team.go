@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ZertGraf/avito-test/internal/domain"
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
@@ -26,7 +27,7 @@ func (r *Team) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
 
-	err := r.db.QueryRow(ctx, query, teamName).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, teamName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check team exists: %w", err)
 	}
@@ -36,34 +37,34 @@ func (r *Team) TeamExists(ctx context.Context, teamName string) (bool, error) {
 
 // CreateTeamWithMembers creates a team and all its members atomically.
 // Uses upsert for members to handle concurrent insertions.
+// Returns ErrUserInOtherTeam if a member already belongs to another team.
 func (r *Team) CreateTeamWithMembers(ctx context.Context, team *domain.Team) (*domain.Team, error) {
 	err := r.withTx(ctx, func(tx pgx.Tx) error {
 		// 1. Создаем команду
 		_, err := tx.Exec(ctx,
-			`INSERT INTO teams (team_name, created_at) VALUES ($1, NOW())`,
+			`INSERT INTO teams (team_name, reviewer_strategy, min_reviewers, max_reviewers, required_approvals,
+			                    auto_reassign_on_absence, review_sla_hours, escalate_overdue, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())`,
 			team.TeamName,
+			team.ReviewerStrategy,
+			team.MinReviewers,
+			team.MaxReviewers,
+			team.RequiredApprovals,
+			team.AutoReassignOnAbsence,
+			team.ReviewSLAHours,
+			team.EscalateOverdue,
 		)
 		if err != nil {
 			return fmt.Errorf("insert team: %w", err)
 		}
 
+		if err := r.insertFallbacks(ctx, tx, team.TeamName, team.FallbackTeams); err != nil {
+			return err
+		}
+
 		for _, member := range team.Members {
-			_, err := tx.Exec(ctx, `
-				INSERT INTO users (user_id, username, team_name, is_active, created_at)
-				VALUES ($1, $2, $3, $4, NOW())
-				ON CONFLICT (user_id) 
-				DO UPDATE SET 
-					username = EXCLUDED.username,
-					team_name = EXCLUDED.team_name,
-					is_active = EXCLUDED.is_active
-			`,
-				member.UserID,
-				member.Username,
-				team.TeamName,
-				member.IsActive,
-			)
-			if err != nil {
-				return fmt.Errorf("upsert user %s: %w", member.UserID, err)
+			if err := r.upsertMember(ctx, tx, team.TeamName, member); err != nil {
+				return err
 			}
 		}
 
@@ -83,16 +84,30 @@ func (r *Team) GetTeamWithMembers(ctx context.Context, teamName string) (*domain
 	query := `
         SELECT 
             t.team_name,
+            t.reviewer_strategy,
+            t.min_reviewers,
+            t.max_reviewers,
+            t.required_approvals,
+            t.auto_reassign_on_absence,
+            t.review_sla_hours,
+            t.escalate_overdue,
+            ARRAY(
+                SELECT f.fallback_team_name
+                FROM team_fallbacks f
+                WHERE f.team_name = t.team_name
+                ORDER BY f.position
+            ) as fallback_teams,
             COALESCE(u.user_id, '') as user_id,
             COALESCE(u.username, '') as username,
-            COALESCE(u.is_active, false) as is_active
+            COALESCE(u.is_active, false) as is_active,
+            u.max_open_reviews
         FROM teams t
         LEFT JOIN users u ON u.team_name = t.team_name
         WHERE t.team_name = $1
         ORDER BY u.user_id
     `
 
-	rows, err := r.db.Query(ctx, query, teamName)
+	rows, err := conn(ctx, r.db).Query(ctx, query, teamName)
 	if err != nil {
 		return nil, fmt.Errorf("query team: %w", err)
 	}
@@ -102,27 +117,31 @@ func (r *Team) GetTeamWithMembers(ctx context.Context, teamName string) (*domain
 	for rows.Next() {
 		var (
 			tName    string
+			settings domain.TeamSettings
 			userID   string
 			username string
 			isActive bool
+			maxOpen  *int
 		)
 
-		if err := rows.Scan(&tName, &userID, &username, &isActive); err != nil {
+		if err := rows.Scan(&tName, &settings.ReviewerStrategy, &settings.MinReviewers, &settings.MaxReviewers, &settings.RequiredApprovals, &settings.AutoReassignOnAbsence, &settings.ReviewSLAHours, &settings.EscalateOverdue, &settings.FallbackTeams, &userID, &username, &isActive, &maxOpen); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
 		if team == nil {
 			team = &domain.Team{
-				TeamName: tName,
-				Members:  []domain.TeamMember{},
+				TeamName:     tName,
+				TeamSettings: settings,
+				Members:      []domain.TeamMember{},
 			}
 		}
 
 		if userID != "" {
 			team.Members = append(team.Members, domain.TeamMember{
-				UserID:   userID,
-				Username: username,
-				IsActive: isActive,
+				UserID:         userID,
+				Username:       username,
+				IsActive:       isActive,
+				MaxOpenReviews: maxOpen,
 			})
 		}
 	}
@@ -138,9 +157,285 @@ func (r *Team) GetTeamWithMembers(ctx context.Context, teamName string) (*domain
 	return team, nil
 }
 
+// GetSettings retrieves reviewer assignment settings of a team.
+// Returns ErrTeamNotFound if team doesn't exist.
+func (r *Team) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	query := `
+		SELECT
+			t.reviewer_strategy,
+			t.min_reviewers,
+			t.max_reviewers,
+			t.required_approvals,
+			t.auto_reassign_on_absence,
+			t.review_sla_hours,
+			t.escalate_overdue,
+			ARRAY(
+				SELECT f.fallback_team_name
+				FROM team_fallbacks f
+				WHERE f.team_name = t.team_name
+				ORDER BY f.position
+			) AS fallback_teams
+		FROM teams t
+		WHERE t.team_name = $1
+	`
+
+	var settings domain.TeamSettings
+	err := conn(ctx, r.db).QueryRow(ctx, query, teamName).Scan(
+		&settings.ReviewerStrategy,
+		&settings.MinReviewers,
+		&settings.MaxReviewers,
+		&settings.RequiredApprovals,
+		&settings.AutoReassignOnAbsence,
+		&settings.ReviewSLAHours,
+		&settings.EscalateOverdue,
+		&settings.FallbackTeams,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("get team settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpdateSettings overwrites reviewer assignment settings of a team,
+// including the ordered list of fallback teams.
+// Returns ErrTeamNotFound if team doesn't exist.
+func (r *Team) UpdateSettings(ctx context.Context, teamName string, settings *domain.TeamSettings) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, `
+			UPDATE teams
+			SET reviewer_strategy = $1,
+			    min_reviewers = $2,
+			    max_reviewers = $3,
+			    required_approvals = $4,
+			    auto_reassign_on_absence = $5,
+			    review_sla_hours = $6,
+			    escalate_overdue = $7
+			WHERE team_name = $8
+		`,
+			settings.ReviewerStrategy,
+			settings.MinReviewers,
+			settings.MaxReviewers,
+			settings.RequiredApprovals,
+			settings.AutoReassignOnAbsence,
+			settings.ReviewSLAHours,
+			settings.EscalateOverdue,
+			teamName,
+		)
+		if err != nil {
+			return fmt.Errorf("update team settings: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrTeamNotFound
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, teamName)
+		if err != nil {
+			return fmt.Errorf("delete fallback teams: %w", err)
+		}
+
+		return r.insertFallbacks(ctx, tx, teamName, settings.FallbackTeams)
+	})
+}
+
+// AddMember adds a user to a team, creating the user if needed.
+// Existing members of the team get their data updated.
+// Returns ErrUserInOtherTeam if the user belongs to another team.
+func (r *Team) AddMember(ctx context.Context, teamName string, member domain.TeamMember) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		return r.upsertMember(ctx, tx, teamName, member)
+	})
+}
+
+// upsertMember inserts a team member or updates a teamless user or a member of the same team.
+func (r *Team) upsertMember(ctx context.Context, tx pgx.Tx, teamName string, member domain.TeamMember) error {
+	result, err := tx.Exec(ctx, `
+		INSERT INTO users (user_id, username, team_name, is_active, max_open_reviews, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) 
+		DO UPDATE SET 
+			username = EXCLUDED.username,
+			team_name = EXCLUDED.team_name,
+			is_active = EXCLUDED.is_active,
+			max_open_reviews = EXCLUDED.max_open_reviews
+		WHERE users.team_name IS NULL OR users.team_name = EXCLUDED.team_name
+	`,
+		member.UserID,
+		member.Username,
+		teamName,
+		member.IsActive,
+		member.MaxOpenReviews,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert user %s: %w", member.UserID, err)
+	}
+
+	// Conflicting row was left untouched: user is a member of another team
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user %s: %w", member.UserID, domain.ErrUserInOtherTeam)
+	}
+
+	return nil
+}
+
+// RemoveMember detaches a user from a team and deactivates them.
+// Returns ErrUserNotFound if the user isn't a member of the team.
+func (r *Team) RemoveMember(ctx context.Context, teamName, userID string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE users
+		SET team_name = NULL, is_active = false
+		WHERE user_id = $1 AND team_name = $2
+	`, userID, teamName)
+	if err != nil {
+		return fmt.Errorf("remove member: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MoveMember moves a user to another team.
+// Returns ErrUserNotFound if user doesn't exist.
+func (r *Team) MoveMember(ctx context.Context, userID, teamName string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE users
+		SET team_name = $1
+		WHERE user_id = $2
+	`, teamName, userID)
+	if err != nil {
+		return fmt.Errorf("move member: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// HasOpenPRs checks if team members author or review any draft or open pull request.
+func (r *Team) HasOpenPRs(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			WHERE u.team_name = $1 AND pr.status IN ('DRAFT', 'OPEN')
+		) OR EXISTS(
+			SELECT 1
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			JOIN users u ON u.user_id = r.reviewer_id
+			WHERE u.team_name = $1 AND pr.status IN ('DRAFT', 'OPEN')
+		)
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, teamName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check team open prs: %w", err)
+	}
+
+	return exists, nil
+}
+
+// DeleteTeam deletes a team together with its settings and ownership rules.
+// Members stay as deactivated teamless users to preserve PR history.
+// Returns ErrTeamNotFound if team doesn't exist.
+func (r *Team) DeleteTeam(ctx context.Context, teamName string) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE users
+			SET team_name = NULL, is_active = false
+			WHERE team_name = $1
+		`, teamName)
+		if err != nil {
+			return fmt.Errorf("detach members: %w", err)
+		}
+
+		result, err := tx.Exec(ctx, `DELETE FROM teams WHERE team_name = $1`, teamName)
+		if err != nil {
+			return fmt.Errorf("delete team: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return domain.ErrTeamNotFound
+		}
+
+		return nil
+	})
+}
+
+// insertFallbacks stores fallback teams preserving their order.
+func (r *Team) insertFallbacks(ctx context.Context, tx pgx.Tx, teamName string, fallbacks []string) error {
+	for position, fallback := range fallbacks {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO team_fallbacks (team_name, fallback_team_name, position)
+			VALUES ($1, $2, $3)
+		`, teamName, fallback, position)
+
+		if err != nil {
+			return fmt.Errorf("insert fallback team %s: %w", fallback, err)
+		}
+	}
+
+	return nil
+}
+
+// LockRoundRobinCursor returns the user_id last picked by the round_robin strategy
+// in the team's candidate pool, or empty string if none was picked yet. The cursor
+// stays locked until the surrounding transaction ends.
+// Returns ErrTeamNotFound if team doesn't exist.
+func (r *Team) LockRoundRobinCursor(ctx context.Context, teamName, pool string) (string, error) {
+	var cursor string
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO round_robin_cursors (team_name, pool)
+		SELECT team_name, $2
+		FROM teams
+		WHERE team_name = $1
+		ON CONFLICT (team_name, pool) DO UPDATE SET pool = EXCLUDED.pool
+		RETURNING last_user_id
+	`, teamName, pool).Scan(&cursor)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrTeamNotFound
+		}
+		return "", fmt.Errorf("lock round robin cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// SetRoundRobinCursor stores the user_id last picked by the round_robin strategy
+// in the team's candidate pool. The cursor must be locked by LockRoundRobinCursor.
+func (r *Team) SetRoundRobinCursor(ctx context.Context, teamName, pool, userID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE round_robin_cursors
+		SET last_user_id = $1
+		WHERE team_name = $2 AND pool = $3
+	`, userID, teamName, pool)
+	if err != nil {
+		return fmt.Errorf("set round robin cursor: %w", err)
+	}
+
+	return nil
+}
+
 // withTx executes a function within a database transaction.
+// Joins the transaction bound to ctx by Transactor, if any.
 // Automatically handles commit/rollback based on error status.
 func (r *Team) withTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Transactor runs a function within one database transaction shared by all
+// repositories: every repository call made with the ctx passed to fn joins it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TxManager struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewTxManager(db *pgxpool.Pool, logger *logger.Logger) *TxManager {
+	return &TxManager{
+		db:     db,
+		logger: logger.Component("repository/tx"),
+	}
+}
+
+// WithinTx executes fn within a transaction bound to the context passed to it.
+// Nested calls join the outer transaction.
+// Automatically handles commit/rollback based on error status.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				m.logger.Error("failed to rollback transaction",
+					"error", rbErr,
+					"original_error", err,
+				)
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// WithinSavepoint executes fn within a savepoint of the transaction bound to ctx,
+// so an error undoes only what fn wrote and the outer transaction can go on.
+// Without a transaction it works like WithinTx.
+func (m *TxManager) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	outer, ok := txFromContext(ctx)
+	if !ok {
+		return m.WithinTx(ctx, fn)
+	}
+
+	tx, err := outer.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			m.logger.Error("failed to rollback to savepoint",
+				"error", rbErr,
+				"original_error", err,
+			)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+type txKey struct{}
+
+// txFromContext returns the transaction started by TxManager, if any.
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// querier is the part of pgx API shared by the pool and transactions.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction bound to ctx, falling back to the pool.
+func conn(ctx context.Context, db *pgxpool.Pool) querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
@@ -22,47 +22,63 @@ func NewUserRepo(db *pgxpool.Pool, logger *logger.Logger) *UserRepo {
 	}
 }
 
-// SetIsActive updates user's activity status and returns updated user.
-func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+// SetIsActive updates user's activity status and returns the user.
+// changed is false if the user already had the given status.
+func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, bool, error) {
 	query := `
 		UPDATE users 
 		SET is_active = $1
-		WHERE user_id = $2
-		RETURNING user_id, username, team_name, is_active
+		WHERE user_id = $2 AND is_active <> $1
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews,
+			timezone, COALESCE(to_char(work_start, 'HH24:MI'), ''), COALESCE(to_char(work_end, 'HH24:MI'), '')
 	`
 
 	var user domain.User
-	err := r.db.QueryRow(ctx, query, isActive, userID).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, isActive, userID).Scan(
 		&user.UserID,
 		&user.Username,
 		&user.TeamName,
 		&user.IsActive,
+		&user.MaxOpenReviews,
+		&user.Timezone,
+		&user.WorkStart,
+		&user.WorkEnd,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrUserNotFound
+			// Either the user is missing or already has the status
+			unchanged, err := r.GetByID(ctx, userID)
+			if err != nil {
+				return nil, false, err
+			}
+			return unchanged, false, nil
 		}
-		return nil, fmt.Errorf("update user: %w", err)
+		return nil, false, fmt.Errorf("update user: %w", err)
 	}
 
-	return &user, nil
+	return &user, true, nil
 }
 
 // GetByID retrieves a user by their unique identifier.
 func (r *UserRepo) GetByID(ctx context.Context, userID string) (*domain.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
+		SELECT user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews,
+			timezone, COALESCE(to_char(work_start, 'HH24:MI'), ''), COALESCE(to_char(work_end, 'HH24:MI'), '')
 		FROM users
 		WHERE user_id = $1
 	`
 
 	var user domain.User
-	err := r.db.QueryRow(ctx, query, userID).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(
 		&user.UserID,
 		&user.Username,
 		&user.TeamName,
 		&user.IsActive,
+		&user.MaxOpenReviews,
+		&user.Timezone,
+		&user.WorkStart,
+		&user.WorkEnd,
 	)
 
 	if err != nil {
@@ -75,36 +91,193 @@ func (r *UserRepo) GetByID(ctx context.Context, userID string) (*domain.User, er
 	return &user, nil
 }
 
-// GetActiveTeamMembers retrieves all active members of a team.
-// Excludes the specified user (typically the PR author or current reviewer).
-func (r *UserRepo) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]*domain.User, error) {
+// GetProfile retrieves a user with the number of OPEN pull requests they review
+// and the number of pull requests they authored.
+func (r *UserRepo) GetProfile(ctx context.Context, userID string) (*domain.UserProfile, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
-		FROM users
-		WHERE team_name = $1 
-		  AND user_id != $2
-		  AND is_active = true
-		ORDER BY user_id
+		SELECT u.user_id, u.username, COALESCE(u.team_name, ''), u.is_active, u.max_open_reviews,
+			u.timezone, COALESCE(to_char(u.work_start, 'HH24:MI'), ''), COALESCE(to_char(u.work_end, 'HH24:MI'), ''),
+			(SELECT COUNT(*)
+			 FROM pr_reviewers r
+			 JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			 WHERE r.reviewer_id = u.user_id AND pr.status = 'OPEN') AS open_reviews,
+			(SELECT COUNT(*) FROM pull_requests pr WHERE pr.author_id = u.user_id) AS authored_prs
+		FROM users u
+		WHERE u.user_id = $1
+	`
+
+	var profile domain.UserProfile
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(
+		&profile.UserID,
+		&profile.Username,
+		&profile.TeamName,
+		&profile.IsActive,
+		&profile.MaxOpenReviews,
+		&profile.Timezone,
+		&profile.WorkStart,
+		&profile.WorkEnd,
+		&profile.OpenReviews,
+		&profile.AuthoredPRs,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user profile: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// SetMaxOpenReviews updates user's open review cap and returns updated user.
+// A nil cap removes the limit.
+func (r *UserRepo) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error) {
+	query := `
+		UPDATE users 
+		SET max_open_reviews = $1
+		WHERE user_id = $2
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews,
+			timezone, COALESCE(to_char(work_start, 'HH24:MI'), ''), COALESCE(to_char(work_end, 'HH24:MI'), '')
 	`
 
-	rows, err := r.db.Query(ctx, query, teamName, excludeUserID)
+	var user domain.User
+	err := conn(ctx, r.db).QueryRow(ctx, query, maxOpenReviews, userID).Scan(
+		&user.UserID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+		&user.MaxOpenReviews,
+		&user.Timezone,
+		&user.WorkStart,
+		&user.WorkEnd,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("update user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// SetWorkingHours updates user's timezone and working hours and returns updated user.
+// Empty workStart and workEnd mean the user has no working hours restriction.
+func (r *UserRepo) SetWorkingHours(ctx context.Context, userID, timezone, workStart, workEnd string) (*domain.User, error) {
+	query := `
+		UPDATE users 
+		SET timezone = $1,
+		    work_start = NULLIF($2, '')::time,
+		    work_end = NULLIF($3, '')::time
+		WHERE user_id = $4
+		RETURNING user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews,
+			timezone, COALESCE(to_char(work_start, 'HH24:MI'), ''), COALESCE(to_char(work_end, 'HH24:MI'), '')
+	`
+
+	var user domain.User
+	err := conn(ctx, r.db).QueryRow(ctx, query, timezone, workStart, workEnd, userID).Scan(
+		&user.UserID,
+		&user.Username,
+		&user.TeamName,
+		&user.IsActive,
+		&user.MaxOpenReviews,
+		&user.Timezone,
+		&user.WorkStart,
+		&user.WorkEnd,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("update user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetActiveTeamMembers retrieves all active and currently available members of a team
+// together with the number of OPEN pull requests each of them currently reviews.
+// Excludes the specified user (typically the PR author or current reviewer).
+func (r *UserRepo) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]*domain.ReviewerCandidate, error) {
+	candidates, err := r.queryCandidates(ctx, "u.team_name = $1 AND u.user_id != $2", teamName, excludeUserID)
 	if err != nil {
 		return nil, fmt.Errorf("query active members: %w", err)
 	}
+
+	return candidates, nil
+}
+
+// GetActiveCandidates retrieves active and currently available users among the given IDs together with
+// the number of OPEN pull requests each of them currently reviews.
+func (r *UserRepo) GetActiveCandidates(ctx context.Context, userIDs []string) ([]*domain.ReviewerCandidate, error) {
+	candidates, err := r.queryCandidates(ctx, "u.user_id = ANY($1)", userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query active candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// queryCandidates retrieves active users who aren't unavailable right now and match
+// the predicate, together with the number of OPEN pull requests each of them reviews.
+// The predicate refers to the users table as u and to args as $1, $2...
+func (r *UserRepo) queryCandidates(ctx context.Context, predicate string, args ...any) ([]*domain.ReviewerCandidate, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			u.user_id,
+			u.username,
+			COALESCE(u.team_name, '') AS team_name,
+			u.is_active,
+			u.max_open_reviews,
+			u.timezone,
+			COALESCE(to_char(u.work_start, 'HH24:MI'), '') AS work_start,
+			COALESCE(to_char(u.work_end, 'HH24:MI'), '') AS work_end,
+			COUNT(pr.pull_request_id) AS open_reviews
+		FROM users u
+		LEFT JOIN pr_reviewers r ON r.reviewer_id = u.user_id
+		LEFT JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			AND pr.status = 'OPEN'
+		WHERE (%s)
+		  AND u.is_active = true
+		  AND NOT EXISTS (
+			  SELECT 1 FROM user_unavailability a
+			  WHERE a.user_id = u.user_id
+			    AND NOW() >= a.starts_at AND NOW() < a.ends_at)
+		GROUP BY u.user_id
+		ORDER BY u.user_id
+	`, predicate)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
-	var users []*domain.User
+	var candidates []*domain.ReviewerCandidate
 	for rows.Next() {
-		var user domain.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		candidate := &domain.ReviewerCandidate{User: &domain.User{}}
+		if err := rows.Scan(
+			&candidate.UserID,
+			&candidate.Username,
+			&candidate.TeamName,
+			&candidate.IsActive,
+			&candidate.MaxOpenReviews,
+			&candidate.Timezone,
+			&candidate.WorkStart,
+			&candidate.WorkEnd,
+			&candidate.OpenReviews,
+		); err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
-		users = append(users, &user)
+		candidates = append(candidates, candidate)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate rows: %w", err)
 	}
 
-	return users, nil
+	return candidates, nil
 }
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+type WebhookRepo struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewWebhookRepo(db *pgxpool.Pool, logger *logger.Logger) *WebhookRepo {
+	return &WebhookRepo{
+		db:     db,
+		logger: logger.Component("repository/webhook"),
+	}
+}
+
+// CreateSubscription persists a webhook subscription.
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	created := &domain.WebhookSubscription{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		EventTypes: sub.EventTypes,
+	}
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types)
+		VALUES ($1, $2, $3)
+		RETURNING subscription_id, created_at
+	`, sub.URL, sub.Secret, eventTypeStrings(sub.EventTypes)).Scan(&created.SubscriptionID, &created.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("insert subscription: %w", err)
+	}
+
+	return created, nil
+}
+
+// ListSubscriptions retrieves all webhook subscriptions in creation order.
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT subscription_id, url, secret, event_types, created_at
+		FROM webhook_subscriptions
+		ORDER BY subscription_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []*domain.WebhookSubscription{}
+	for rows.Next() {
+		sub := &domain.WebhookSubscription{}
+		var eventTypes []string
+		if err := rows.Scan(&sub.SubscriptionID, &sub.URL, &sub.Secret, &eventTypes, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		for _, eventType := range eventTypes {
+			sub.EventTypes = append(sub.EventTypes, domain.EventType(eventType))
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return subs, nil
+}
+
+// DeleteSubscription removes a subscription together with its deliveries.
+// Returns ErrSubscriptionNotFound if subscription doesn't exist.
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, subscriptionID int64) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE subscription_id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// Enqueue creates a pending delivery of the payload for every subscription to the event type.
+// Joins the transaction bound to ctx. Within a transaction the insert runs in a savepoint,
+// so its failure leaves the transaction usable.
+// Returns the number of created deliveries.
+func (r *WebhookRepo) Enqueue(ctx context.Context, eventType domain.EventType, payload []byte) (int64, error) {
+	var q querier = r.db
+	if tx, ok := txFromContext(ctx); ok {
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("begin savepoint: %w", err)
+		}
+		defer savepoint.Rollback(ctx)
+		q = savepoint
+	}
+
+	result, err := q.Exec(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload)
+		SELECT subscription_id, $1, $2
+		FROM webhook_subscriptions
+		WHERE $1 = ANY(event_types)
+	`, string(eventType), payload)
+
+	if err != nil {
+		return 0, fmt.Errorf("insert deliveries: %w", err)
+	}
+
+	if savepoint, ok := q.(pgx.Tx); ok {
+		if err := savepoint.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ClaimDue takes up to limit pending deliveries whose next attempt is due, counts the attempt
+// and postpones the next one by lease, so a crashed sender's deliveries are retried later.
+// Concurrent callers skip each other's rows.
+func (r *WebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + $2::interval
+		FROM webhook_subscriptions s
+		WHERE s.subscription_id = d.subscription_id
+		  AND d.delivery_id IN (
+			  SELECT delivery_id
+			  FROM webhook_deliveries
+			  WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+			  ORDER BY next_attempt_at
+			  LIMIT $1
+			  FOR UPDATE SKIP LOCKED)
+		RETURNING d.delivery_id, d.subscription_id, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at,
+			s.url, s.secret
+	`, limit, lease)
+	if err != nil {
+		return nil, fmt.Errorf("claim deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, true)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered records a successful delivery.
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, deliveryID int64, responseStatus int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'DELIVERED', response_status = $2, last_error = '', delivered_at = NOW()
+		WHERE delivery_id = $1
+	`, deliveryID, responseStatus)
+
+	if err != nil {
+		return fmt.Errorf("mark delivery delivered: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at nextAttemptAt;
+// nil nextAttemptAt means attempts are exhausted and the delivery is FAILED.
+// responseStatus is nil when no response was received.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, deliveryID int64, responseStatus *int, lastError string, nextAttemptAt *time.Time) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4::timestamptz IS NULL THEN 'FAILED' ELSE 'PENDING' END,
+		    response_status = $2,
+		    last_error = $3,
+		    next_attempt_at = COALESCE($4, next_attempt_at)
+		WHERE delivery_id = $1
+	`, deliveryID, responseStatus, lastError, nextAttemptAt)
+
+	if err != nil {
+		return fmt.Errorf("mark delivery failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves the latest deliveries of a subscription, newest first.
+// Empty status means any status.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT delivery_id, subscription_id, event_type, payload, status, attempts,
+			next_attempt_at, response_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, delivery_id DESC
+		LIMIT $3
+	`, subscriptionID, string(status), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// scanDelivery scans a delivery row, followed by subscription's url and secret if withTarget is set.
+func scanDelivery(rows pgx.Rows, withTarget bool) (*domain.WebhookDelivery, error) {
+	delivery := &domain.WebhookDelivery{}
+	dest := []any{
+		&delivery.DeliveryID,
+		&delivery.SubscriptionID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+	if withTarget {
+		dest = append(dest, &delivery.URL, &delivery.Secret)
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("scan delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// eventTypeStrings converts event types for a TEXT[] parameter.
+func eventTypeStrings(eventTypes []domain.EventType) []string {
+	values := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		values = append(values, string(eventType))
+	}
+	return values
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"time"
+)
+
+// maxUnavailabilityReason is the upper bound for unavailability reason length.
+const maxUnavailabilityReason = 1000
+
+// Backoff between failed automatic reassignments of one absence.
+const (
+	absenceRetryBase = time.Minute
+	absenceRetryMax  = time.Hour
+)
+
+type AvailabilityService struct {
+	repo      repository.AvailabilityRepository
+	userRepo  repository.UserRepository
+	tx        repository.Transactor
+	prService *PRService
+	logger    *logger.Logger
+}
+
+func NewAvailabilityService(
+	repo repository.AvailabilityRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	prService *PRService,
+	logger *logger.Logger,
+) *AvailabilityService {
+	return &AvailabilityService{
+		repo:      repo,
+		userRepo:  userRepo,
+		tx:        tx,
+		prService: prService,
+		logger:    logger.Component("service/availability"),
+	}
+}
+
+// AddUnavailability schedules a period when the user can't review.
+// The user is skipped during reviewer assignment while the period lasts.
+func (s *AvailabilityService) AddUnavailability(ctx context.Context, period *domain.Unavailability) (*domain.Unavailability, error) {
+	if err := validateUnavailability(period, time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, period.UserID); err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	created, err := s.repo.Create(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("create unavailability: %w", err)
+	}
+
+	s.logger.Info("unavailability scheduled",
+		"user_id", created.UserID,
+		"starts_at", created.StartsAt,
+		"ends_at", created.EndsAt,
+	)
+
+	return created, nil
+}
+
+// ListUnavailability retrieves current and upcoming unavailability periods of a user.
+func (s *AvailabilityService) ListUnavailability(ctx context.Context, userID string) ([]*domain.Unavailability, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	periods, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list unavailability: %w", err)
+	}
+
+	return periods, nil
+}
+
+// ReassignAbsent reassigns open reviews of users whose absence has started,
+// for teams with auto_reassign_on_absence enabled. Each absence is processed
+// once, in its own transaction. A failed absence is recorded and retried with
+// backoff on later runs without blocking the others. Returns the number of
+// processed absences.
+func (s *AvailabilityService) ReassignAbsent(ctx context.Context) (int, error) {
+	processed := 0
+
+	for {
+		var period *domain.Unavailability
+
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			period, err = s.repo.ClaimStarted(ctx)
+			if err != nil || period == nil {
+				return err
+			}
+
+			reassignments, err := s.prService.ReassignOpenReviews(ctx, period.UserID, domain.SystemActor, domain.ReasonUnavailable)
+			if err != nil {
+				return fmt.Errorf("reassign open reviews of %s: %w", period.UserID, err)
+			}
+
+			s.logger.Info("reviews of absent user reassigned",
+				"user_id", period.UserID,
+				"unavailability_id", period.UnavailabilityID,
+				"reassigned", len(reassignments),
+			)
+
+			return s.repo.MarkProcessed(ctx, period.UnavailabilityID)
+		})
+
+		if err != nil {
+			// Nothing was claimed, so there is nothing to record the failure on
+			if period == nil {
+				return processed, err
+			}
+
+			if err := s.markFailed(ctx, period, err); err != nil {
+				return processed, err
+			}
+			continue
+		}
+
+		if period == nil {
+			return processed, nil
+		}
+
+		processed++
+	}
+}
+
+// markFailed records a failed reassignment of the absence and postpones the next attempt.
+func (s *AvailabilityService) markFailed(ctx context.Context, period *domain.Unavailability, reassignErr error) error {
+	attempts := period.ReassignAttempts + 1
+	next := time.Now().Add(retryDelay(absenceRetryBase, absenceRetryMax, attempts))
+
+	s.logger.Warn("failed to reassign reviews of absent user",
+		"user_id", period.UserID,
+		"unavailability_id", period.UnavailabilityID,
+		"attempt", attempts,
+		"error", reassignErr,
+		"next_attempt_at", next,
+	)
+
+	if err := s.repo.MarkFailed(ctx, period.UnavailabilityID, reassignErr.Error(), next); err != nil {
+		return fmt.Errorf("record reassignment failure: %w", err)
+	}
+
+	return nil
+}
+
+// retryDelay returns the delay after the given number of failed attempts:
+// base doubled for every attempt after the first, capped at max.
+func retryDelay(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// validateUnavailability checks that the period is well-formed and not over yet.
+func validateUnavailability(period *domain.Unavailability, now time.Time) error {
+	if period.UserID == "" {
+		return errors.New("user_id is required")
+	}
+	if period.StartsAt.IsZero() || period.EndsAt.IsZero() {
+		return errors.New("starts_at and ends_at are required")
+	}
+	if !period.EndsAt.After(period.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if !period.EndsAt.After(now) {
+		return errors.New("period is already over")
+	}
+	if len(period.Reason) > maxUnavailabilityReason {
+		return fmt.Errorf("reason must be at most %d characters", maxUnavailabilityReason)
+	}
+	return nil
+}
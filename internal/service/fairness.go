@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"math"
+	"sort"
+)
+
+// defaultFairnessThreshold is the deviation, in standard deviations, above which
+// a member is reported as overloaded or underloaded.
+const defaultFairnessThreshold = 1.5
+
+// maxFairnessThreshold is the upper bound for the fairness threshold.
+const maxFairnessThreshold = 10
+
+type FairnessService struct {
+	statsRepo repository.StatsRepository
+	teamRepo  repository.TeamRepository
+	userRepo  repository.UserRepository
+	prRepo    repository.PRRepository
+	tx        repository.Transactor
+	prService *PRService
+	logger    *logger.Logger
+}
+
+func NewFairnessService(
+	statsRepo repository.StatsRepository,
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PRRepository,
+	tx repository.Transactor,
+	prService *PRService,
+	logger *logger.Logger,
+) *FairnessService {
+	return &FairnessService{
+		statsRepo: statsRepo,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+		prRepo:    prRepo,
+		tx:        tx,
+		prService: prService,
+		logger:    logger.Component("service/fairness"),
+	}
+}
+
+// Report compares each team member's share of review assignments within the window
+// with an equal share among active members, and proposes moves of open reviews
+// from overloaded members to the least loaded ones. Zero threshold means default.
+func (s *FairnessService) Report(ctx context.Context, teamName string, window domain.StatsWindow, threshold float64) (*domain.FairnessReport, error) {
+	if threshold == 0 {
+		threshold = defaultFairnessThreshold
+	}
+
+	if threshold < 0 || threshold > maxFairnessThreshold {
+		return nil, fmt.Errorf("%w: threshold must be between 0 and %d", domain.ErrInvalidRequest, maxFairnessThreshold)
+	}
+
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("check team exists: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	stats, err := s.statsRepo.UserStats(ctx, teamName, window)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", err)
+	}
+
+	report := buildFairnessReport(teamName, window, threshold, stats)
+
+	report.Proposals, err = s.propose(ctx, teamName, report)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("fairness report built",
+		"team_name", teamName,
+		"members", len(report.Members),
+		"mean", report.Mean,
+		"std_dev", report.StdDev,
+		"proposals", len(report.Proposals),
+	)
+
+	return report, nil
+}
+
+// buildFairnessReport computes shares and deviations. Inactive members keep
+// their counts but don't take part in mean and standard deviation.
+func buildFairnessReport(teamName string, window domain.StatsWindow, threshold float64, stats []*domain.UserStats) *domain.FairnessReport {
+	report := &domain.FairnessReport{
+		TeamName:  teamName,
+		Window:    window,
+		Threshold: threshold,
+		Members:   make([]domain.FairnessEntry, 0, len(stats)),
+		Proposals: []domain.RebalanceMove{},
+	}
+
+	active := 0
+	for _, st := range stats {
+		if st.IsActive {
+			active++
+			report.Total += st.Assigned
+		}
+	}
+
+	if active == 0 {
+		for _, st := range stats {
+			report.Members = append(report.Members, fairnessEntry(st, domain.FairnessInactive))
+		}
+		return report
+	}
+
+	report.Mean = float64(report.Total) / float64(active)
+
+	variance := 0.0
+	for _, st := range stats {
+		if st.IsActive {
+			diff := float64(st.Assigned) - report.Mean
+			variance += diff * diff
+		}
+	}
+	report.StdDev = math.Sqrt(variance / float64(active))
+
+	for _, st := range stats {
+		if !st.IsActive {
+			entry := fairnessEntry(st, domain.FairnessInactive)
+			if report.Total > 0 {
+				entry.Share = float64(st.Assigned) / float64(report.Total)
+			}
+			report.Members = append(report.Members, entry)
+			continue
+		}
+
+		entry := fairnessEntry(st, domain.FairnessOK)
+		entry.ExpectedShare = 1 / float64(active)
+		if report.Total > 0 {
+			entry.Share = float64(st.Assigned) / float64(report.Total)
+		}
+		if report.StdDev > 0 {
+			entry.Deviation = (float64(st.Assigned) - report.Mean) / report.StdDev
+		}
+
+		switch {
+		case entry.Deviation > threshold:
+			entry.Status = domain.FairnessOverloaded
+		case entry.Deviation < -threshold:
+			entry.Status = domain.FairnessUnderloaded
+		}
+
+		report.Members = append(report.Members, entry)
+	}
+
+	return report
+}
+
+func fairnessEntry(st *domain.UserStats, status domain.FairnessStatus) domain.FairnessEntry {
+	return domain.FairnessEntry{
+		UserID:      st.UserID,
+		Username:    st.Username,
+		IsActive:    st.IsActive,
+		Assigned:    st.Assigned,
+		OpenReviews: st.OpenReviews,
+		Status:      status,
+	}
+}
+
+// propose greedily moves open reviews of overloaded members to the least loaded
+// available members while the gap between them is at least two assignments.
+// Code owner assignments are never moved.
+func (s *FairnessService) propose(ctx context.Context, teamName string, report *domain.FairnessReport) ([]domain.RebalanceMove, error) {
+	donors := make([]domain.FairnessEntry, 0)
+	for _, entry := range report.Members {
+		if entry.Status == domain.FairnessOverloaded {
+			donors = append(donors, entry)
+		}
+	}
+
+	moves := []domain.RebalanceMove{}
+	if len(donors) == 0 {
+		return moves, nil
+	}
+
+	sort.Slice(donors, func(i, j int) bool {
+		return donors[i].Assigned > donors[j].Assigned
+	})
+
+	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, teamName, "")
+	if err != nil {
+		return nil, fmt.Errorf("get active team members: %w", err)
+	}
+
+	load := make(map[string]int, len(report.Members))
+	for _, entry := range report.Members {
+		load[entry.UserID] = entry.Assigned
+	}
+
+	// reviewers of pull requests touched so far, including proposed changes
+	reviewers := make(map[string]map[string]bool)
+
+	for _, donor := range donors {
+		prs, err := s.prRepo.GetByReviewer(ctx, donor.UserID, domain.PRStatusOpen, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("get reviews by user: %w", err)
+		}
+
+		for _, short := range prs {
+			if float64(load[donor.UserID]) <= report.Mean {
+				break
+			}
+
+			pr, err := s.prRepo.GetByID(ctx, short.PullRequestID)
+			if err != nil {
+				return nil, fmt.Errorf("get pr: %w", err)
+			}
+
+			if isCodeOwnerReview(pr, donor.UserID) {
+				continue
+			}
+
+			assigned, ok := reviewers[pr.PullRequestID]
+			if !ok {
+				assigned = make(map[string]bool, len(pr.AssignedReviewers))
+				for _, reviewerID := range pr.AssignedReviewers {
+					assigned[reviewerID] = true
+				}
+				reviewers[pr.PullRequestID] = assigned
+			}
+
+			if !assigned[donor.UserID] {
+				continue
+			}
+
+			receiver := leastLoaded(candidates, load, func(c *domain.ReviewerCandidate) bool {
+				return c.UserID != pr.AuthorID && !assigned[c.UserID] && !c.AtCapacity()
+			})
+			if receiver == nil || load[donor.UserID]-load[receiver.UserID] < 2 {
+				continue
+			}
+
+			moves = append(moves, domain.RebalanceMove{
+				PullRequestID:  pr.PullRequestID,
+				FromReviewerID: donor.UserID,
+				ToReviewerID:   receiver.UserID,
+			})
+
+			load[donor.UserID]--
+			load[receiver.UserID]++
+			receiver.OpenReviews++
+			delete(assigned, donor.UserID)
+			assigned[receiver.UserID] = true
+		}
+	}
+
+	return moves, nil
+}
+
+// leastLoaded returns the eligible candidate with the lowest load, ties broken by user ID.
+func leastLoaded(candidates []*domain.ReviewerCandidate, load map[string]int, eligible func(*domain.ReviewerCandidate) bool) *domain.ReviewerCandidate {
+	var best *domain.ReviewerCandidate
+	for _, candidate := range candidates {
+		if !eligible(candidate) {
+			continue
+		}
+		if best == nil || load[candidate.UserID] < load[best.UserID] ||
+			(load[candidate.UserID] == load[best.UserID] && candidate.UserID < best.UserID) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// isCodeOwnerReview reports whether the user was assigned to pr as a code owner.
+func isCodeOwnerReview(pr *domain.PullRequest, userID string) bool {
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.UserID == userID {
+			return reviewer.Reason == domain.ReasonCodeOwner
+		}
+	}
+	return false
+}
+
+// ApplyRebalance applies confirmed moves in one transaction: either all
+// reviews are moved or none. Proposals that went stale fail the whole batch.
+func (s *FairnessService) ApplyRebalance(ctx context.Context, moves []domain.RebalanceMove, actorID string) ([]domain.Reassignment, error) {
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("%w: moves are required", domain.ErrInvalidRequest)
+	}
+
+	for _, move := range moves {
+		if move.PullRequestID == "" || move.FromReviewerID == "" || move.ToReviewerID == "" {
+			return nil, fmt.Errorf("%w: pull_request_id, from_reviewer_id and to_reviewer_id are required", domain.ErrInvalidRequest)
+		}
+	}
+
+	reassignments := make([]domain.Reassignment, 0, len(moves))
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, move := range moves {
+			_, err := s.prService.MoveReviewer(ctx, move.PullRequestID, move.FromReviewerID, move.ToReviewerID, actorID, domain.ReasonRebalanced)
+			if err != nil {
+				return fmt.Errorf("move review of %s on %s: %w", move.FromReviewerID, move.PullRequestID, err)
+			}
+
+			reassignments = append(reassignments, domain.Reassignment{
+				PullRequestID: move.PullRequestID,
+				OldReviewerID: move.FromReviewerID,
+				NewReviewerID: move.ToReviewerID,
+			})
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("rebalance applied",
+		"moves", len(reassignments),
+		"actor_id", actorID,
+	)
+
+	return reassignments, nil
+}
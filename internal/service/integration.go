@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	. "github.com/go-ozzo/ozzo-validation"
+)
+
+type IntegrationService struct {
+	identityRepo repository.IdentityRepository
+	userRepo     repository.UserRepository
+	prService    *PRService
+	logger       *logger.Logger
+}
+
+func NewIntegrationService(
+	identityRepo repository.IdentityRepository,
+	userRepo repository.UserRepository,
+	prService *PRService,
+	logger *logger.Logger,
+) *IntegrationService {
+	return &IntegrationService{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		prService:    prService,
+		logger:       logger.Component("service/integration"),
+	}
+}
+
+// AddIdentity maps a login on a git hosting to an existing user.
+// Mapping an already mapped login replaces its user.
+func (s *IntegrationService) AddIdentity(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error) {
+	if err := validateIdentity(identity); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, identity.UserID); err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	saved, err := s.identityRepo.SaveIdentity(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("save identity: %w", err)
+	}
+
+	s.logger.Info("external identity mapped",
+		"provider", saved.Provider,
+		"login", saved.Login,
+		"user_id", saved.UserID,
+	)
+
+	return saved, nil
+}
+
+// ListIdentities retrieves login mappings of the provider; empty provider means all of them.
+func (s *IntegrationService) ListIdentities(ctx context.Context, provider domain.Provider) ([]*domain.ExternalIdentity, error) {
+	if provider != "" && !provider.IsValid() {
+		return nil, fmt.Errorf("%w: unknown provider %q", domain.ErrInvalidRequest, provider)
+	}
+
+	identities, err := s.identityRepo.ListIdentities(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("list identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// RemoveIdentity deletes the mapping of a login.
+func (s *IntegrationService) RemoveIdentity(ctx context.Context, provider domain.Provider, login string) error {
+	if err := s.identityRepo.DeleteIdentity(ctx, provider, login); err != nil {
+		return fmt.Errorf("delete identity: %w", err)
+	}
+
+	s.logger.Info("external identity removed", "provider", provider, "login", login)
+
+	return nil
+}
+
+// HandlePREvent applies a pull request change reported by a git hosting.
+// Git hostings redeliver webhooks, so an event already applied returns the current
+// state of the pull request instead of an error. The author must be mapped to a user;
+// unmapped actors are recorded as SystemActor.
+func (s *IntegrationService) HandlePREvent(ctx context.Context, event domain.ExternalPREvent) (*domain.PullRequest, error) {
+	prID := event.PullRequestID()
+
+	actorID, err := s.resolveActor(ctx, event.Provider, event.ActorLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr *domain.PullRequest
+	switch event.Action {
+	case domain.ExternalOpened:
+		pr, err = s.create(ctx, event)
+
+	case domain.ExternalReady:
+		var response *CreatePRResponse
+		response, err = s.prService.MarkReady(ctx, prID, actorID, nil)
+		if err == nil {
+			pr = response.PR
+		}
+		pr, err = s.alreadyApplied(ctx, prID, domain.PRStatusOpen, pr, err)
+
+	case domain.ExternalClosed:
+		pr, err = s.prService.ClosePR(ctx, prID, actorID)
+		pr, err = s.alreadyApplied(ctx, prID, domain.PRStatusClosed, pr, err)
+
+	case domain.ExternalMerged:
+		pr, err = s.prService.MergePR(ctx, prID, actorID)
+		// The pull request is merged on the hosting already: missing approvals
+		// or a draft/closed status here can't undo it
+		if errors.Is(err, domain.ErrNotEnoughApprovals) || errors.Is(err, domain.ErrInvalidTransition) {
+			pr, err = s.prService.RecordExternalMerge(ctx, prID, actorID)
+		}
+
+	case domain.ExternalReopened:
+		var response *CreatePRResponse
+		response, err = s.prService.ReopenPR(ctx, prID, actorID)
+		if err == nil {
+			pr = response.PR
+		}
+		pr, err = s.alreadyApplied(ctx, prID, domain.PRStatusOpen, pr, err)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", domain.ErrInvalidRequest, event.Action)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("external pr event applied",
+		"provider", event.Provider,
+		"action", event.Action,
+		"pr_id", prID,
+		"status", pr.Status,
+		"actor_id", actorID,
+	)
+
+	return pr, nil
+}
+
+// create creates the pull request of an opened event, authored by the mapped user.
+func (s *IntegrationService) create(ctx context.Context, event domain.ExternalPREvent) (*domain.PullRequest, error) {
+	authorID, err := s.identityRepo.ResolveUser(ctx, event.Provider, event.AuthorLogin)
+	if err != nil {
+		return nil, fmt.Errorf("resolve author: %w", err)
+	}
+
+	response, err := s.prService.CreatePR(ctx, CreatePRInput{
+		PullRequestID:   event.PullRequestID(),
+		PullRequestName: event.Title,
+		AuthorID:        authorID,
+		Draft:           event.Draft,
+	})
+	if errors.Is(err, domain.ErrPRExists) {
+		return s.prService.GetPR(ctx, event.PullRequestID())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return response.PR, nil
+}
+
+// alreadyApplied turns ErrInvalidTransition into success when the pull request
+// is already in the status the transition leads to.
+func (s *IntegrationService) alreadyApplied(
+	ctx context.Context,
+	prID string,
+	status domain.PRStatus,
+	pr *domain.PullRequest,
+	err error,
+) (*domain.PullRequest, error) {
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		return pr, err
+	}
+
+	current, getErr := s.prService.GetPR(ctx, prID)
+	if getErr != nil || current.Status != status {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// resolveActor maps the login of whoever made the change to a user.
+func (s *IntegrationService) resolveActor(ctx context.Context, provider domain.Provider, login string) (string, error) {
+	if login == "" {
+		return domain.SystemActor, nil
+	}
+
+	userID, err := s.identityRepo.ResolveUser(ctx, provider, login)
+	if errors.Is(err, domain.ErrIdentityNotFound) {
+		return domain.SystemActor, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve actor: %w", err)
+	}
+
+	return userID, nil
+}
+
+// validateIdentity checks the provider, login and user of a mapping.
+func validateIdentity(identity *domain.ExternalIdentity) error {
+	if identity == nil {
+		return errors.New("identity is nil")
+	}
+
+	return ValidateStruct(identity,
+		Field(&identity.Provider,
+			Required,
+			By(func(value interface{}) error {
+				if provider, _ := value.(domain.Provider); !provider.IsValid() {
+					return fmt.Errorf("unknown provider %q", provider)
+				}
+				return nil
+			}),
+		),
+		Field(&identity.Login,
+			Required,
+			Length(1, 255),
+		),
+		Field(&identity.UserID,
+			Required,
+			Length(1, 255),
+		),
+	)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/pkg/webhook"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"strconv"
+)
+
+// EventNotifier records domain events emitted by services.
+// Callers emit events within Transactor.WithinTx together with the change they describe
+// and fail the change if the event can't be recorded.
+type EventNotifier interface {
+	Notify(ctx context.Context, event domain.Event) error
+}
+
+// Publisher delivers outbox events to their consumers.
+// Publish is called within a savepoint of the relay transaction: an error undoes
+// what was written for the event and leaves it and every later one unpublished
+// until the next relay run.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// EventHandler consumes events published in-process.
+type EventHandler func(ctx context.Context, event *domain.OutboxEvent) error
+
+// InProcessPublisher passes events to handlers subscribed in the same process.
+// Handlers run within the relay transaction, so whatever they write through
+// repositories is committed together with the event being marked as published.
+type InProcessPublisher struct {
+	handlers []EventHandler
+}
+
+func NewInProcessPublisher(handlers ...EventHandler) *InProcessPublisher {
+	return &InProcessPublisher{handlers: handlers}
+}
+
+// Publish calls every handler in subscription order, stopping at the first error.
+func (p *InProcessPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	for _, handler := range p.handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HTTPPublisher posts events to an external endpoint, e.g. a message broker gateway.
+// Requests are signed like webhook deliveries; the event ID is sent as delivery ID
+// so the receiver can drop duplicates.
+type HTTPPublisher struct {
+	sender *webhook.Sender
+	url    string
+	secret string
+}
+
+func NewHTTPPublisher(sender *webhook.Sender, url, secret string) *HTTPPublisher {
+	return &HTTPPublisher{
+		sender: sender,
+		url:    url,
+		secret: secret,
+	}
+}
+
+// Publish posts the event payload and fails unless the endpoint answers 2xx.
+func (p *HTTPPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	_, err := p.sender.Send(ctx, webhook.Request{
+		URL:        p.url,
+		Secret:     p.secret,
+		Event:      string(event.Type),
+		DeliveryID: strconv.FormatInt(event.EventID, 10),
+		Body:       event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("post event %d: %w", event.EventID, err)
+	}
+	return nil
+}
+
+type OutboxService struct {
+	repo      repository.OutboxRepository
+	tx        repository.Transactor
+	publisher Publisher
+	batchSize int
+	logger    *logger.Logger
+}
+
+func NewOutboxService(
+	repo repository.OutboxRepository,
+	tx repository.Transactor,
+	publisher Publisher,
+	batchSize int,
+	logger *logger.Logger,
+) *OutboxService {
+	return &OutboxService{
+		repo:      repo,
+		tx:        tx,
+		publisher: publisher,
+		batchSize: batchSize,
+		logger:    logger.Component("service/outbox"),
+	}
+}
+
+// Notify stores the event in the outbox. Called within the transaction of
+// the change, it makes the event durable exactly when the change is.
+func (s *OutboxService) Notify(ctx context.Context, event domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event %s: %w", event.Type, err)
+	}
+
+	if err := s.repo.Append(ctx, event.Type, payload, event.OccurredAt); err != nil {
+		return fmt.Errorf("append event %s: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// Relay publishes a batch of unpublished events in the order they were stored.
+// Stops at the first publishing error so that later events never overtake it.
+// Only one relay runs at a time; concurrent calls return immediately.
+// Returns the number of published events.
+func (s *OutboxService) Relay(ctx context.Context) (int, error) {
+	var published []int64
+	var publishErr error
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		locked, err := s.repo.TryLockRelay(ctx)
+		if err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		events, err := s.repo.ListUnpublished(ctx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			err := s.tx.WithinSavepoint(ctx, func(ctx context.Context) error {
+				return s.publisher.Publish(ctx, event)
+			})
+			if err != nil {
+				publishErr = fmt.Errorf("publish event %d: %w", event.EventID, err)
+				break
+			}
+			published = append(published, event.EventID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+
+		return s.repo.MarkPublished(ctx, published)
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("relay outbox: %w", err)
+	}
+
+	if len(published) > 0 {
+		s.logger.Info("outbox events published", "count", len(published))
+	}
+
+	return len(published), publishErr
+}
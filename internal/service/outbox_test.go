@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeStore is an in-memory database whose writes are undone like a transaction's.
+type fakeStore struct {
+	writes []string
+}
+
+// fakeTransactor undoes writes made by fn to the store when fn fails.
+type fakeTransactor struct {
+	store *fakeStore
+}
+
+func (t fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	mark := len(t.store.writes)
+	if err := fn(ctx); err != nil {
+		t.store.writes = t.store.writes[:mark]
+		return err
+	}
+	return nil
+}
+
+func (t fakeTransactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.WithinTx(ctx, fn)
+}
+
+type fakeOutboxRepository struct {
+	events    []*domain.OutboxEvent
+	published []int64
+}
+
+func (r *fakeOutboxRepository) Append(ctx context.Context, eventType domain.EventType, payload []byte, occurredAt time.Time) error {
+	return nil
+}
+
+func (r *fakeOutboxRepository) TryLockRelay(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeOutboxRepository) ListUnpublished(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	return r.events, nil
+}
+
+func (r *fakeOutboxRepository) MarkPublished(ctx context.Context, eventIDs []int64) error {
+	r.published = append(r.published, eventIDs...)
+	return nil
+}
+
+func TestRelayUndoesFailedEvent(t *testing.T) {
+	store := &fakeStore{}
+	repo := &fakeOutboxRepository{
+		events: []*domain.OutboxEvent{{EventID: 1}, {EventID: 2}},
+	}
+
+	writing := func(name string) EventHandler {
+		return func(ctx context.Context, event *domain.OutboxEvent) error {
+			store.writes = append(store.writes, fmt.Sprintf("%s %d", name, event.EventID))
+			return nil
+		}
+	}
+	failingOn := func(eventID int64) EventHandler {
+		return func(ctx context.Context, event *domain.OutboxEvent) error {
+			if event.EventID == eventID {
+				return errors.New("endpoint is down")
+			}
+			return nil
+		}
+	}
+
+	log, err := logger.New(&logger.Config{Level: "error", Format: "json"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	publisher := NewInProcessPublisher(writing("delivery"), writing("sync task"), failingOn(2))
+	s := NewOutboxService(repo, fakeTransactor{store: store}, publisher, 10, log)
+
+	published, err := s.Relay(context.Background())
+	if err == nil {
+		t.Fatal("Relay() error = nil, want publishing error")
+	}
+	if published != 1 {
+		t.Errorf("published = %d, want 1", published)
+	}
+	if !reflect.DeepEqual(repo.published, []int64{1}) {
+		t.Errorf("marked published = %v, want [1]", repo.published)
+	}
+
+	// Event 2 is relayed again later, so nothing written for it may stay
+	want := []string{"delivery 1", "sync task 1"}
+	if !reflect.DeepEqual(store.writes, want) {
+		t.Errorf("writes = %v, want %v", store.writes, want)
+	}
+}
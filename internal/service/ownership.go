@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"regexp"
+	"strings"
+)
+
+type OwnershipService struct {
+	repo     repository.OwnershipRepository
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+	logger   *logger.Logger
+}
+
+func NewOwnershipService(
+	repo repository.OwnershipRepository,
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	logger *logger.Logger,
+) *OwnershipService {
+	return &OwnershipService{
+		repo:     repo,
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+		logger:   logger.Component("service/ownership"),
+	}
+}
+
+// AddRule validates and stores a new ownership rule for a team.
+func (s *OwnershipService) AddRule(ctx context.Context, rule *domain.OwnershipRule) (*domain.OwnershipRule, error) {
+	if err := validateRule(rule); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	exists, err := s.teamRepo.TeamExists(ctx, rule.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("check team exists: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	for _, ownerID := range rule.Owners {
+		if _, err := s.userRepo.GetByID(ctx, ownerID); err != nil {
+			return nil, fmt.Errorf("get owner %s: %w", ownerID, err)
+		}
+	}
+
+	created, err := s.repo.CreateRule(ctx, rule)
+	if err != nil {
+		return nil, fmt.Errorf("create rule: %w", err)
+	}
+
+	s.logger.Info("ownership rule added",
+		"rule_id", created.RuleID,
+		"team_name", created.TeamName,
+		"pattern", created.Pattern,
+		"owners_count", len(created.Owners),
+	)
+
+	return created, nil
+}
+
+// ListRules retrieves ownership rules of a team in precedence order (last wins).
+func (s *OwnershipService) ListRules(ctx context.Context, teamName string) ([]*domain.OwnershipRule, error) {
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("check team exists: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	rules, err := s.repo.ListRules(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("list rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// RemoveRule deletes an ownership rule.
+func (s *OwnershipService) RemoveRule(ctx context.Context, ruleID int64) error {
+	if err := s.repo.DeleteRule(ctx, ruleID); err != nil {
+		return fmt.Errorf("delete rule: %w", err)
+	}
+
+	s.logger.Info("ownership rule removed", "rule_id", ruleID)
+
+	return nil
+}
+
+// validateRule checks that rule has a team, a valid pattern and at least one owner.
+func validateRule(rule *domain.OwnershipRule) error {
+	if rule == nil {
+		return errors.New("rule is nil")
+	}
+	if rule.TeamName == "" {
+		return errors.New("team_name is required")
+	}
+	if len(rule.Owners) == 0 {
+		return errors.New("at least one owner is required")
+	}
+	if _, err := compilePattern(rule.Pattern); err != nil {
+		return err
+	}
+	return nil
+}
+
+// resolveOwners returns owners of the given files. For every file the last
+// matching rule wins, as in CODEOWNERS. Owners are returned in first-seen order.
+func resolveOwners(rules []*domain.OwnershipRule, files []string) []string {
+	compiled := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		// Invalid patterns are rejected on creation, skip them defensively
+		compiled[i], _ = compilePattern(rule.Pattern)
+	}
+
+	owners := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, file := range files {
+		file = strings.TrimPrefix(file, "/")
+
+		for i := len(rules) - 1; i >= 0; i-- {
+			if compiled[i] == nil || !compiled[i].MatchString(file) {
+				continue
+			}
+
+			for _, owner := range rules[i].Owners {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+
+	return owners
+}
+
+// compilePattern converts a CODEOWNERS glob into a regular expression:
+//   - "*" matches anything except "/", "?" matches a single non-"/" character
+//   - "**" matches across directories
+//   - a leading "/" or a "/" in the middle anchors the pattern to the repository root,
+//     otherwise it matches at any depth
+//   - a pattern matching a directory also matches everything inside it
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || p == "/" {
+		return nil, errors.New("pattern is required")
+	}
+	if strings.HasPrefix(p, "!") {
+		return nil, errors.New("negated patterns are not supported")
+	}
+
+	anchored := strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "/"), "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && i+1 < len(p) && p[i+1] == '*':
+			if i+2 < len(p) && p[i+2] == '/' {
+				// "**/" matches zero or more directories
+				b.WriteString("(?:.*/)?")
+				i += 2
+			} else {
+				b.WriteString(".*")
+				i++
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return re, nil
+}
@@ -7,52 +7,130 @@ import (
 	"github.com/ZertGraf/avito-test/internal/domain"
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
 	"github.com/ZertGraf/avito-test/internal/repository"
-	"math/rand"
-	"sync"
 	"time"
 )
 
 type PRService struct {
-	prRepo   repository.PRRepository
-	userRepo repository.UserRepository
-	logger   *logger.Logger
-	random   *rand.Rand
-	mu       *sync.Mutex
+	prRepo        repository.PRRepository
+	userRepo      repository.UserRepository
+	teamRepo      repository.TeamRepository
+	ownershipRepo repository.OwnershipRepository
+	tx            repository.Transactor
+	notifier      EventNotifier
+	logger        *logger.Logger
+	selectors     map[domain.ReviewerStrategy]ReviewerSelector
 }
 
 func NewPRService(
 	prRepo repository.PRRepository,
 	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	ownershipRepo repository.OwnershipRepository,
+	tx repository.Transactor,
+	notifier EventNotifier,
 	logger *logger.Logger,
 ) *PRService {
-	mu := new(sync.Mutex)
 	return &PRService{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		logger:   logger.Component("service/pr"),
-		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
-		mu:       mu,
+		prRepo:        prRepo,
+		userRepo:      userRepo,
+		teamRepo:      teamRepo,
+		ownershipRepo: ownershipRepo,
+		tx:            tx,
+		notifier:      notifier,
+		logger:        logger.Component("service/pr"),
+		selectors:     newSelectors(newLockedRand(), teamRepo, tx),
 	}
 }
 
-// GetReviewsByUser retrieves all pull requests assigned to a specific reviewer.
-func (s *PRService) GetReviewsByUser(ctx context.Context, userID string) ([]*domain.PullRequestShort, error) {
-	prs, err := s.prRepo.GetByReviewer(ctx, userID)
+// GetPR retrieves a pull request with its reviewers.
+func (s *PRService) GetPR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr: %w", err)
+	}
+
+	s.logger.Info("pr retrieved",
+		"pr_id", prID,
+		"status", pr.Status,
+	)
+
+	return pr, nil
+}
+
+// GetReviewsByUser retrieves a page of pull requests assigned to a specific reviewer, newest first.
+// Empty status means OPEN: merged and closed reviews are listed only when asked for.
+// cursor is the next_cursor of the previous page; zero limit means default page size.
+func (s *PRService) GetReviewsByUser(ctx context.Context, userID string, status domain.PRStatus, cursor string, limit int) (*domain.ReviewPage, error) {
+	if status == "" {
+		status = domain.PRStatusOpen
+	}
+
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidRequest, status)
+	}
+
+	limit, err := pageSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *domain.Cursor
+	if cursor != "" {
+		after, err = domain.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// One extra row tells whether there is a next page
+	prs, err := s.prRepo.GetByReviewer(ctx, userID, status, after, limit+1)
 	if err != nil {
 		return nil, fmt.Errorf("get reviews by user: %w", err)
 	}
 
+	page := &domain.ReviewPage{PullRequests: prs}
+	if len(prs) > limit {
+		page.PullRequests = prs[:limit]
+		last := page.PullRequests[limit-1]
+		page.NextCursor = domain.Cursor{Time: *last.CreatedAt, ID: last.PullRequestID}.Encode()
+	}
+
 	s.logger.Info("retrieved user reviews",
 		"user_id", userID,
-		"count", len(prs),
+		"status", status,
+		"count", len(page.PullRequests),
+		"has_more", page.NextCursor != "",
 	)
 
-	return prs, nil
+	return page, nil
 }
 
-// CreatePR creates a new pull request and automatically assigns reviewers from author's team.
-func (s *PRService) CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
-	exists, err := s.prRepo.Exists(ctx, prID)
+// AssignmentWarning explains why fewer reviewers than expected were assigned.
+type AssignmentWarning string
+
+const (
+	// WarningAllAtCapacity means every active candidate reached their open review cap.
+	WarningAllAtCapacity AssignmentWarning = "ALL_CANDIDATES_AT_CAPACITY"
+)
+
+type CreatePRResponse struct {
+	PR       *domain.PullRequest `json:"pr"`
+	Warnings []AssignmentWarning `json:"warnings,omitempty"`
+}
+
+// CreatePRInput describes a pull request to create.
+type CreatePRInput struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	Files           []string // изменённые пути для поиска code owners
+	Draft           bool     // черновик создаётся без ревьюеров
+}
+
+// CreatePR creates a new pull request and automatically assigns reviewers,
+// unless it's a draft: drafts get reviewers only when marked ready.
+func (s *PRService) CreatePR(ctx context.Context, input CreatePRInput) (*CreatePRResponse, error) {
+	exists, err := s.prRepo.Exists(ctx, input.PullRequestID)
 	if err != nil {
 		return nil, fmt.Errorf("check pr exists: %w", err)
 	}
@@ -60,76 +138,378 @@ func (s *PRService) CreatePR(ctx context.Context, prID, prName, authorID string)
 		return nil, domain.ErrPRExists
 	}
 
-	author, err := s.userRepo.GetByID(ctx, authorID)
+	author, err := s.userRepo.GetByID(ctx, input.AuthorID)
 	if err != nil {
 		return nil, fmt.Errorf("get author: %w", err)
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, author.TeamName, authorID)
-	if err != nil {
-		return nil, fmt.Errorf("get team members: %w", err)
-	}
+	status := domain.PRStatusDraft
+	reviewers := []domain.Reviewer{}
+	var warnings []AssignmentWarning
 
-	// Randomly select up to 2 reviewers
-	reviewers := s.selectReviewers(candidates, 2)
+	if !input.Draft {
+		status = domain.PRStatusOpen
+		reviewers, warnings, err = s.assignReviewers(ctx, author, input.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	pr := &domain.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   prName,
-		AuthorID:          authorID,
-		Status:            domain.PRStatusOpen,
-		AssignedReviewers: reviewers,
+		PullRequestID:     input.PullRequestID,
+		PullRequestName:   input.PullRequestName,
+		AuthorID:          input.AuthorID,
+		Status:            status,
+		AssignedReviewers: reviewerIDs(reviewers),
+		Reviewers:         reviewers,
 	}
 
-	if err := s.prRepo.Create(ctx, pr); err != nil {
-		return nil, fmt.Errorf("create pr: %w", err)
+	var created *domain.PullRequest
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.Create(ctx, pr, input.AuthorID); err != nil {
+			return fmt.Errorf("create pr: %w", err)
+		}
+
+		var err error
+		created, err = s.prRepo.GetByID(ctx, input.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("get created pr: %w", err)
+		}
+
+		if err := s.notifier.Notify(ctx, domain.NewEvent(domain.EventPRCreated, domain.PREventData{PR: created})); err != nil {
+			return err
+		}
+
+		return s.notifyAssigned(ctx, created.PullRequestID, reviewers)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("pr created",
-		"pr_id", prID,
-		"author_id", authorID,
+		"pr_id", input.PullRequestID,
+		"author_id", input.AuthorID,
+		"status", status,
 		"reviewers_count", len(reviewers),
+		"warnings", warnings,
 	)
 
-	created, err := s.prRepo.GetByID(ctx, prID)
+	return &CreatePRResponse{PR: created, Warnings: warnings}, nil
+}
+
+// assignReviewers picks reviewers for a pull request of the given author.
+// Owners of the changed files are picked first, then slots are topped up from
+// author's team and after that from the team's fallback teams in order.
+// Members who reached their open review cap are never picked. Authors removed from
+// their team get no reviewers. Fails with ErrNotEnoughReviewers if fewer than team's
+// min_reviewers can be assigned.
+func (s *PRService) assignReviewers(
+	ctx context.Context,
+	author *domain.User,
+	files []string,
+) ([]domain.Reviewer, []AssignmentWarning, error) {
+	settings, err := s.authorSettings(ctx, author)
 	if err != nil {
-		return nil, fmt.Errorf("get created pr: %w", err)
+		return nil, nil, err
 	}
 
-	return created, nil
+	reviewers := []domain.Reviewer{}
+	atCapacity := 0
+
+	if author.TeamName != "" {
+		excluded := map[string]bool{author.UserID: true}
+
+		// Code owners of the touched paths go first
+		owners, ownersAtCapacity, err := s.pickOwners(ctx, author.TeamName, settings, files, excluded)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Top up to max_reviewers from author's team first, then from fallback teams
+		teams := teamOrder(author.TeamName, settings.FallbackTeams)
+		rest, restAtCapacity, err := s.pickReviewers(ctx, teams, settings.MaxReviewers-len(owners), excluded)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reviewers = append(owners, rest...)
+		atCapacity = ownersAtCapacity + restAtCapacity
+	}
+
+	var warnings []AssignmentWarning
+	if len(reviewers) == 0 && atCapacity > 0 {
+		warnings = append(warnings, WarningAllAtCapacity)
+	}
+
+	if len(reviewers) < settings.MinReviewers {
+		return nil, nil, fmt.Errorf("%w: need %d, available %d",
+			domain.ErrNotEnoughReviewers, settings.MinReviewers, len(reviewers))
+	}
+
+	return reviewers, warnings, nil
 }
 
-// selectReviewers randomly selects reviewers from a pool of candidates.
-// Uses Fisher-Yates shuffle for uniform distribution.
-func (s *PRService) selectReviewers(candidates []*domain.User, maxCount int) []string {
-	if len(candidates) == 0 {
-		return []string{}
+// pickOwners selects up to team's max_reviewers among active owners of the given files
+// using the team's strategy. Owners may belong to any team. Users in excluded are never
+// picked; picked users are added to excluded. Also returns how many owners were skipped
+// because they reached their open review cap.
+func (s *PRService) pickOwners(
+	ctx context.Context,
+	teamName string,
+	settings *domain.TeamSettings,
+	files []string,
+	excluded map[string]bool,
+) ([]domain.Reviewer, int, error) {
+	if len(files) == 0 {
+		return []domain.Reviewer{}, 0, nil
 	}
 
-	count := min(maxCount, len(candidates))
+	rules, err := s.ownershipRepo.ListRules(ctx, teamName)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list ownership rules: %w", err)
+	}
 
-	// Create a copy to avoid modifying the original slice
-	shuffled := make([]*domain.User, len(candidates))
-	copy(shuffled, candidates)
+	ownerIDs := resolveOwners(rules, files)
+	if len(ownerIDs) == 0 {
+		return []domain.Reviewer{}, 0, nil
+	}
 
-	// Thread-safe shuffle
-	s.mu.Lock()
-	s.random.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
-	s.mu.Unlock()
+	owners, err := s.userRepo.GetActiveCandidates(ctx, ownerIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get owners: %w", err)
+	}
 
-	// Extract IDs from shuffled candidates
-	reviewers := make([]string, count)
-	for i := 0; i < count; i++ {
-		reviewers[i] = shuffled[i].UserID
+	candidates, atCapacity := filterCandidates(owners, excluded)
+	pool := CandidatePool{TeamName: teamName, Name: poolOwners}
+	selected, err := s.selectInHours(ctx, pool, settings, candidates, settings.MaxReviewers)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return reviewers
+	reviewers := make([]domain.Reviewer, 0, len(selected))
+	for _, pick := range selected {
+		reviewers = append(reviewers, domain.Reviewer{
+			UserID:   pick.candidate.UserID,
+			TeamName: pick.candidate.TeamName,
+			Reason:   domain.ReasonCodeOwner,
+			Explanation: pick.explain(fmt.Sprintf("code owner of changed files, picked by %s strategy",
+				settings.ReviewerStrategy)),
+		})
+		excluded[pick.candidate.UserID] = true
+	}
+
+	return reviewers, atCapacity, nil
+}
+
+// pickReviewers selects up to count reviewers from the given teams in order.
+// Each team is asked only for the slots the previous teams couldn't fill, using
+// its own selection strategy. Users in excluded are never picked; picked users
+// are added to excluded. Also returns how many otherwise eligible members were
+// skipped because they reached their open review cap.
+func (s *PRService) pickReviewers(
+	ctx context.Context,
+	teams []string,
+	count int,
+	excluded map[string]bool,
+) ([]domain.Reviewer, int, error) {
+	reviewers := make([]domain.Reviewer, 0, count)
+	atCapacity := 0
+
+	for i, teamName := range teams {
+		if len(reviewers) >= count {
+			break
+		}
+
+		candidates, capped, err := s.loadCandidates(ctx, teamName, excluded)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		atCapacity += capped
+		if len(candidates) == 0 {
+			continue
+		}
+
+		settings, err := s.teamRepo.GetSettings(ctx, teamName)
+		if err != nil {
+			return nil, 0, fmt.Errorf("get team settings: %w", err)
+		}
+
+		reason, source := domain.ReasonTeamMember, "member of team"
+		if i > 0 {
+			reason, source = domain.ReasonFallbackTeam, "member of fallback team"
+		}
+
+		pool := CandidatePool{TeamName: teamName, Name: poolMembers}
+		selected, err := s.selectInHours(ctx, pool, settings, candidates, count-len(reviewers))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, pick := range selected {
+			reviewers = append(reviewers, domain.Reviewer{
+				UserID:   pick.candidate.UserID,
+				TeamName: teamName,
+				Reason:   reason,
+				Explanation: pick.explain(fmt.Sprintf("%s %s, picked by %s strategy",
+					source, teamName, settings.ReviewerStrategy)),
+			})
+			excluded[pick.candidate.UserID] = true
+		}
+	}
+
+	return reviewers, atCapacity, nil
+}
+
+// loadCandidates retrieves active team members that are not excluded and are
+// below their open review cap. Also returns how many members were skipped
+// because they reached the cap.
+func (s *PRService) loadCandidates(
+	ctx context.Context,
+	teamName string,
+	excluded map[string]bool,
+) ([]*domain.ReviewerCandidate, int, error) {
+	members, err := s.userRepo.GetActiveTeamMembers(ctx, teamName, "")
+	if err != nil {
+		return nil, 0, fmt.Errorf("get team members: %w", err)
+	}
+
+	candidates, atCapacity := filterCandidates(members, excluded)
+	return candidates, atCapacity, nil
+}
+
+// filterCandidates drops excluded users and users at their open review cap.
+// Also returns how many non-excluded users were dropped because of the cap.
+func filterCandidates(users []*domain.ReviewerCandidate, excluded map[string]bool) ([]*domain.ReviewerCandidate, int) {
+	candidates := make([]*domain.ReviewerCandidate, 0, len(users))
+	atCapacity := 0
+	for _, user := range users {
+		if excluded[user.UserID] {
+			continue
+		}
+		if user.AtCapacity() {
+			atCapacity++
+			continue
+		}
+		candidates = append(candidates, user)
+	}
+	return candidates, atCapacity
+}
+
+// teamOrder returns the primary team followed by its fallbacks without duplicates.
+func teamOrder(primary string, fallbacks []string) []string {
+	teams := make([]string, 0, len(fallbacks)+1)
+	seen := make(map[string]bool, len(fallbacks)+1)
+	for _, team := range append([]string{primary}, fallbacks...) {
+		if !seen[team] {
+			seen[team] = true
+			teams = append(teams, team)
+		}
+	}
+	return teams
+}
+
+// pick is a candidate chosen by selectInHours.
+type pick struct {
+	candidate *domain.ReviewerCandidate
+	// outOfHours is set when the candidate was picked outside their working
+	// hours because nobody within working hours was available
+	outOfHours bool
+}
+
+// explain completes the description of why the candidate was picked.
+func (p pick) explain(base string) string {
+	explanation := fmt.Sprintf("%s, %d open reviews", base, p.candidate.OpenReviews)
+
+	switch {
+	case p.outOfHours:
+		return explanation + ", outside working hours: nobody within working hours was available"
+	case p.candidate.HasWorkingHours():
+		return explanation + ", within working hours"
+	default:
+		return explanation
+	}
+}
+
+// selectInHours selects up to count candidates with the team's strategy, preferring
+// those currently within their working hours and falling back to the rest.
+// Both groups are rotated over as separate pools, so falling back doesn't
+// move the position of the candidates within working hours.
+func (s *PRService) selectInHours(
+	ctx context.Context,
+	pool CandidatePool,
+	settings *domain.TeamSettings,
+	candidates []*domain.ReviewerCandidate,
+	count int,
+) ([]pick, error) {
+	if count <= 0 || len(candidates) == 0 {
+		return []pick{}, nil
+	}
+
+	now := time.Now()
+	inHours := make([]*domain.ReviewerCandidate, 0, len(candidates))
+	outOfHours := make([]*domain.ReviewerCandidate, 0)
+	for _, candidate := range candidates {
+		if candidate.InWorkingHours(now) {
+			inHours = append(inHours, candidate)
+		} else {
+			outOfHours = append(outOfHours, candidate)
+		}
+	}
+
+	selector := s.selectorFor(pool.TeamName, settings)
+
+	selected, err := selector.Select(ctx, pool, inHours, count)
+	if err != nil {
+		return nil, fmt.Errorf("select reviewers: %w", err)
+	}
+
+	picks := make([]pick, 0, count)
+	for _, candidate := range selected {
+		picks = append(picks, pick{candidate: candidate})
+	}
+
+	if len(picks) < count && len(outOfHours) > 0 {
+		selected, err := selector.Select(ctx, pool.outOfHours(), outOfHours, count-len(picks))
+		if err != nil {
+			return nil, fmt.Errorf("select reviewers: %w", err)
+		}
+		for _, candidate := range selected {
+			picks = append(picks, pick{candidate: candidate, outOfHours: true})
+		}
+	}
+
+	return picks, nil
+}
+
+// selectorFor returns the reviewer selector configured for the team.
+// Falls back to random selection for unknown strategies.
+func (s *PRService) selectorFor(teamName string, settings *domain.TeamSettings) ReviewerSelector {
+	selector, ok := s.selectors[settings.ReviewerStrategy]
+	if !ok {
+		s.logger.Warn("unknown reviewer strategy, falling back to random",
+			"team", teamName,
+			"strategy", settings.ReviewerStrategy,
+		)
+		selector = s.selectors[domain.ReviewerStrategyRandom]
+	}
+
+	return selector
+}
+
+// reviewerIDs extracts user IDs from reviewer assignments.
+func reviewerIDs(reviewers []domain.Reviewer) []string {
+	ids := make([]string, len(reviewers))
+	for i, reviewer := range reviewers {
+		ids[i] = reviewer.UserID
+	}
+	return ids
 }
 
 // MergePR marks a pull request as merged. Idempotent operation.
-func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+// Refuses with ErrNotEnoughApprovals until the author team's required_approvals is reached.
+// Empty actorID is recorded as SystemActor.
+func (s *PRService) MergePR(ctx context.Context, prID, actorID string) (*domain.PullRequest, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, fmt.Errorf("get pr: %w", err)
@@ -143,17 +523,88 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 		return pr, nil
 	}
 
-	if err := s.prRepo.Merge(ctx, prID); err != nil {
-		return nil, fmt.Errorf("merge pr: %w", err)
+	if !pr.Status.CanTransitionTo(domain.PRStatusMerged) {
+		return nil, fmt.Errorf("%w: %s -> %s", domain.ErrInvalidTransition, pr.Status, domain.PRStatusMerged)
 	}
 
-	merged, err := s.prRepo.GetByID(ctx, prID)
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
-		return nil, fmt.Errorf("get merged pr: %w", err)
+		return nil, fmt.Errorf("get author: %w", err)
+	}
+
+	settings, err := s.authorSettings(ctx, author)
+	if err != nil {
+		return nil, err
+	}
+
+	if approvals := pr.Approvals(); approvals < settings.RequiredApprovals {
+		return nil, fmt.Errorf("%w: need %d, got %d",
+			domain.ErrNotEnoughApprovals, settings.RequiredApprovals, approvals)
+	}
+
+	return s.merge(ctx, pr, actorID, domain.PRStatusOpen)
+}
+
+// RecordExternalMerge records a merge that already happened on a git hosting.
+// Approvals and transition rules aren't checked: the fact can't be refused, so a draft,
+// open or closed pull request becomes MERGED. Idempotent operation.
+func (s *PRService) RecordExternalMerge(ctx context.Context, prID, actorID string) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr: %w", err)
+	}
+
+	if pr.Status == domain.PRStatusMerged {
+		return pr, nil
+	}
+
+	s.logger.Warn("recording external merge bypassing merge checks",
+		"pr_id", prID,
+		"status", pr.Status,
+		"approvals", pr.Approvals(),
+	)
+
+	return s.merge(ctx, pr, actorID, domain.PRStatusDraft, domain.PRStatusOpen, domain.PRStatusClosed)
+}
+
+// merge marks pr as merged if it's still in one of the from statuses and emits pr.merged.
+// A pull request merged concurrently is returned as is.
+func (s *PRService) merge(ctx context.Context, pr *domain.PullRequest, actorID string, from ...domain.PRStatus) (*domain.PullRequest, error) {
+	prID := pr.PullRequestID
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	var merged *domain.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.Merge(ctx, prID, actorID, from...); err != nil {
+			return fmt.Errorf("merge pr: %w", err)
+		}
+
+		var err error
+		merged, err = s.prRepo.GetByID(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("get merged pr: %w", err)
+		}
+
+		return s.notifier.Notify(ctx, domain.NewEvent(domain.EventPRMerged, domain.PREventData{PR: merged}))
+	})
+
+	if err != nil {
+		// Handle concurrent merge scenario
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			checkPR, checkErr := s.prRepo.GetByID(ctx, prID)
+			if checkErr == nil && checkPR.Status == domain.PRStatusMerged {
+				return checkPR, nil
+			}
+		}
+		return nil, err
 	}
 
 	s.logger.Info("pr merged successfully",
 		"pr_id", prID,
+		"previous_status", pr.Status,
 		"merged_at", merged.MergedAt,
 		"reviewers_count", len(merged.AssignedReviewers),
 	)
@@ -161,31 +612,96 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (*domain.PullReque
 	return merged, nil
 }
 
-// ReassignReviewer replaces an assigned reviewer with a new random team member.
+// ReassignReviewer replaces an assigned reviewer with a member of the replaced reviewer's team,
+// falling back to the fallback teams of the author's team when nobody there is available.
+// Actor and reason are recorded in reviewer history; empty values get defaults.
 // Returns the updated PR and new reviewer ID.
-func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*domain.PullRequest, string, error) {
+func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID, actorID, reason string) (*domain.PullRequest, string, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, "", fmt.Errorf("get pr: %w", err)
 	}
 
-	if pr.Status == domain.PRStatusMerged {
-		return nil, "", domain.ErrPRMerged
+	if err := checkOpen(pr); err != nil {
+		return nil, "", err
 	}
 
 	if !s.isAssigned(pr.AssignedReviewers, oldUserID) {
 		return nil, "", domain.ErrNotAssigned
 	}
 
+	newReviewer, err := s.pickReplacement(ctx, pr, oldUserID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if reason != "" {
+		newReviewer.Reason = reason
+	} else {
+		newReviewer.Reason = domain.ReasonReassigned
+	}
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	// Atomically replace reviewer in database
+	var updated *domain.PullRequest
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.ReplaceReviewer(ctx, prID, oldUserID, newReviewer, actorID); err != nil {
+			return fmt.Errorf("replace reviewer: %w", err)
+		}
+
+		var err error
+		updated, err = s.prRepo.GetByID(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("get updated pr: %w", err)
+		}
+
+		return s.notifyReassigned(ctx, prID, oldUserID, newReviewer)
+	})
+
+	if err != nil {
+		// Handle concurrent merge scenario
+		if errors.Is(err, domain.ErrNotAssigned) {
+			checkPR, checkErr := s.prRepo.GetByID(ctx, prID)
+			if checkErr == nil {
+				if err := checkOpen(checkPR); err != nil {
+					return nil, "", err
+				}
+			}
+			return nil, "", domain.ErrNotAssigned
+		}
+		return nil, "", err
+	}
+
+	s.logger.Info("reviewer reassigned",
+		"pr_id", prID,
+		"old_reviewer", oldUserID,
+		"new_reviewer", newReviewer.UserID,
+		"team", newReviewer.TeamName,
+		"actor_id", actorID,
+	)
+
+	return updated, newReviewer.UserID, nil
+}
+
+// pickReplacement selects a reviewer to replace oldUserID on pr.
+// Looks in the replaced reviewer's team first, then in fallback teams of the author's team.
+func (s *PRService) pickReplacement(ctx context.Context, pr *domain.PullRequest, oldUserID string) (domain.Reviewer, error) {
 	oldUser, err := s.userRepo.GetByID(ctx, oldUserID)
 	if err != nil {
-		return nil, "", fmt.Errorf("get old reviewer: %w", err)
+		return domain.Reviewer{}, fmt.Errorf("get old reviewer: %w", err)
 	}
 
-	// Get all active team members
-	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, oldUser.TeamName, "")
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
-		return nil, "", fmt.Errorf("get team members: %w", err)
+		return domain.Reviewer{}, fmt.Errorf("get author: %w", err)
+	}
+
+	authorSettings, err := s.authorSettings(ctx, author)
+	if err != nil {
+		return domain.Reviewer{}, err
 	}
 
 	// Build exclusion list: author + current reviewers
@@ -195,49 +711,333 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldUserID string
 		excluded[reviewerID] = true
 	}
 
-	// Filter eligible candidates
-	eligible := make([]*domain.User, 0)
-	for _, candidate := range candidates {
-		if !excluded[candidate.UserID] {
-			eligible = append(eligible, candidate)
+	teams := teamOrder(oldUser.TeamName, authorSettings.FallbackTeams)
+	picked, atCapacity, err := s.pickReviewers(ctx, teams, 1, excluded)
+	if err != nil {
+		return domain.Reviewer{}, err
+	}
+
+	if len(picked) == 0 {
+		reason, err := s.noCandidateReason(ctx, teams, excluded, atCapacity)
+		if err != nil {
+			return domain.Reviewer{}, err
 		}
+		return domain.Reviewer{}, noCandidateError{reason: reason}
 	}
 
-	if len(eligible) == 0 {
-		return nil, "", domain.ErrNoCandidate
+	return picked[0], nil
+}
+
+// noCandidateReason explains why nobody in teams could be picked: some members were
+// at their open review cap, some active members are absent, or there is nobody else.
+func (s *PRService) noCandidateReason(
+	ctx context.Context,
+	teams []string,
+	excluded map[string]bool,
+	atCapacity int,
+) (domain.UnreassignedReason, error) {
+	if atCapacity > 0 {
+		return domain.UnreassignedAllAtCapacity, nil
 	}
 
-	// Select random replacement reviewer
-	s.mu.Lock()
-	newReviewer := eligible[s.random.Intn(len(eligible))]
-	s.mu.Unlock()
+	// Active members who weren't candidates are absent
+	for _, teamName := range teams {
+		if teamName == "" {
+			continue
+		}
 
-	// Atomically replace reviewer in database
-	if err := s.prRepo.ReplaceReviewer(ctx, prID, oldUserID, newReviewer.UserID); err != nil {
+		team, err := s.teamRepo.GetTeamWithMembers(ctx, teamName)
+		if err != nil {
+			return "", fmt.Errorf("get team: %w", err)
+		}
+
+		for _, member := range team.Members {
+			if member.IsActive && !excluded[member.UserID] {
+				return domain.UnreassignedNobodyAvailable, nil
+			}
+		}
+	}
+
+	return domain.UnreassignedNoCandidate, nil
+}
+
+// noCandidateError is ErrNoCandidate with the reason nobody could be picked.
+type noCandidateError struct {
+	reason domain.UnreassignedReason
+}
+
+func (e noCandidateError) Error() string {
+	return fmt.Sprintf("%s: %s", domain.ErrNoCandidate, e.reason)
+}
+
+func (e noCandidateError) Unwrap() error {
+	return domain.ErrNoCandidate
+}
+
+// authorSettings retrieves settings of the author's team.
+// Authors removed from their team get default settings.
+func (s *PRService) authorSettings(ctx context.Context, author *domain.User) (*domain.TeamSettings, error) {
+	if author.TeamName == "" {
+		settings := domain.DefaultTeamSettings()
+		return &settings, nil
+	}
+
+	settings, err := s.teamRepo.GetSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("get team settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// ReassignOpenReviews replaces userID on every open pull request they review.
+// Pull requests without a suitable replacement lose the reviewer instead.
+// Callers wanting all-or-nothing semantics run it within Transactor.WithinTx.
+func (s *PRService) ReassignOpenReviews(ctx context.Context, userID, actorID, reason string) ([]domain.Reassignment, error) {
+	prs, err := s.prRepo.GetByReviewer(ctx, userID, domain.PRStatusOpen, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get reviews by user: %w", err)
+	}
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	reassignments := []domain.Reassignment{}
+	for _, short := range prs {
+		pr, err := s.prRepo.GetByID(ctx, short.PullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("get pr: %w", err)
+		}
+
+		reassignment := domain.Reassignment{
+			PullRequestID: pr.PullRequestID,
+			OldReviewerID: userID,
+		}
+
+		newReviewer, err := s.pickReplacement(ctx, pr, userID)
+		var noCandidate noCandidateError
+		switch {
+		case errors.As(err, &noCandidate):
+			reassignment.Reason = noCandidate.reason
+			err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := s.prRepo.RemoveReviewer(ctx, pr.PullRequestID, userID, actorID, reason); err != nil {
+					return fmt.Errorf("remove reviewer from %s: %w", pr.PullRequestID, err)
+				}
+				return s.notifyUnassigned(ctx, pr.PullRequestID, userID, reason)
+			})
+			if err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		default:
+			newReviewer.Reason = reason
+			err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := s.prRepo.ReplaceReviewer(ctx, pr.PullRequestID, userID, newReviewer, actorID); err != nil {
+					return fmt.Errorf("replace reviewer on %s: %w", pr.PullRequestID, err)
+				}
+				return s.notifyReassigned(ctx, pr.PullRequestID, userID, newReviewer)
+			})
+			if err != nil {
+				return nil, err
+			}
+			reassignment.NewReviewerID = newReviewer.UserID
+		}
+
+		reassignments = append(reassignments, reassignment)
+	}
+
+	s.logger.Info("open reviews reassigned",
+		"user_id", userID,
+		"count", len(reassignments),
+		"actor_id", actorID,
+	)
+
+	return reassignments, nil
+}
+
+// MoveReviewer replaces an assigned reviewer with the given user.
+// The new reviewer must be active, available and below their open review cap.
+func (s *PRService) MoveReviewer(ctx context.Context, prID, oldUserID, newUserID, actorID, reason string) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr: %w", err)
+	}
+
+	if err := checkOpen(pr); err != nil {
+		return nil, err
+	}
+
+	if !s.isAssigned(pr.AssignedReviewers, oldUserID) {
+		return nil, domain.ErrNotAssigned
+	}
+
+	if newUserID == pr.AuthorID || s.isAssigned(pr.AssignedReviewers, newUserID) {
+		return nil, fmt.Errorf("%w: %s is the author or already a reviewer of %s", domain.ErrInvalidRequest, newUserID, prID)
+	}
+
+	candidates, err := s.userRepo.GetActiveCandidates(ctx, []string{newUserID})
+	if err != nil {
+		return nil, fmt.Errorf("get candidate: %w", err)
+	}
+
+	if len(candidates) == 0 || candidates[0].AtCapacity() {
+		return nil, fmt.Errorf("%w: %s is inactive, absent or at capacity", domain.ErrNoCandidate, newUserID)
+	}
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	candidate := candidates[0]
+	newReviewer := domain.Reviewer{
+		UserID:      candidate.UserID,
+		TeamName:    candidate.TeamName,
+		Reason:      reason,
+		Explanation: fmt.Sprintf("chosen by %s, %d open reviews", actorID, candidate.OpenReviews),
+	}
+
+	var updated *domain.PullRequest
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.ReplaceReviewer(ctx, prID, oldUserID, newReviewer, actorID); err != nil {
+			return fmt.Errorf("replace reviewer: %w", err)
+		}
+
+		var err error
+		updated, err = s.prRepo.GetByID(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("get updated pr: %w", err)
+		}
+
+		return s.notifyReassigned(ctx, prID, oldUserID, newReviewer)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("reviewer moved",
+		"pr_id", prID,
+		"old_reviewer", oldUserID,
+		"new_reviewer", newUserID,
+		"reason", reason,
+		"actor_id", actorID,
+	)
+
+	return updated, nil
+}
+
+// SubmitReview records a reviewer's decision on an open pull request.
+func (s *PRService) SubmitReview(ctx context.Context, prID, reviewerID string, decision domain.ReviewDecision) (*domain.PullRequest, error) {
+	if !decision.IsValid() {
+		return nil, fmt.Errorf("%w: unknown decision %q", domain.ErrInvalidRequest, decision)
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr: %w", err)
+	}
+
+	if err := checkOpen(pr); err != nil {
+		return nil, err
+	}
+
+	if !s.isAssigned(pr.AssignedReviewers, reviewerID) {
+		return nil, domain.ErrNotAssigned
+	}
+
+	if err := s.prRepo.SetDecision(ctx, prID, reviewerID, decision); err != nil {
 		// Handle concurrent merge scenario
 		if errors.Is(err, domain.ErrNotAssigned) {
 			checkPR, checkErr := s.prRepo.GetByID(ctx, prID)
-			if checkErr == nil && checkPR.Status == domain.PRStatusMerged {
-				return nil, "", domain.ErrPRMerged
+			if checkErr == nil {
+				if err := checkOpen(checkPR); err != nil {
+					return nil, err
+				}
 			}
-			return nil, "", domain.ErrNotAssigned
+			return nil, domain.ErrNotAssigned
 		}
-		return nil, "", fmt.Errorf("replace reviewer: %w", err)
+		return nil, fmt.Errorf("set decision: %w", err)
 	}
 
 	updated, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return nil, "", fmt.Errorf("get updated pr: %w", err)
+		return nil, fmt.Errorf("get updated pr: %w", err)
 	}
 
-	s.logger.Info("reviewer reassigned",
+	s.logger.Info("review submitted",
 		"pr_id", prID,
-		"old_reviewer", oldUserID,
-		"new_reviewer", newReviewer.UserID,
-		"team", oldUser.TeamName,
+		"reviewer_id", reviewerID,
+		"decision", decision,
+		"approvals", updated.Approvals(),
 	)
 
-	return updated, newReviewer.UserID, nil
+	return updated, nil
+}
+
+// GetHistory retrieves the reviewer assignment history of a pull request.
+func (s *PRService) GetHistory(ctx context.Context, prID string) ([]*domain.ReviewerEvent, error) {
+	exists, err := s.prRepo.Exists(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("check pr exists: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrPRNotFound
+	}
+
+	events, err := s.prRepo.GetEvents(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr history: %w", err)
+	}
+
+	return events, nil
+}
+
+// notifyAssigned emits reviewer.assigned for every reviewer assigned to a pull request.
+func (s *PRService) notifyAssigned(ctx context.Context, prID string, reviewers []domain.Reviewer) error {
+	for _, reviewer := range reviewers {
+		err := s.notifier.Notify(ctx, domain.NewEvent(domain.EventReviewerAssigned, domain.ReviewerEventData{
+			PullRequestID: prID,
+			ReviewerID:    reviewer.UserID,
+			Reason:        reviewer.Reason,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyReassigned emits reviewer.reassigned for a reviewer replaced on a pull request.
+func (s *PRService) notifyReassigned(ctx context.Context, prID, oldUserID string, newReviewer domain.Reviewer) error {
+	return s.notifier.Notify(ctx, domain.NewEvent(domain.EventReviewerReassigned, domain.ReviewerEventData{
+		PullRequestID:      prID,
+		ReviewerID:         newReviewer.UserID,
+		PreviousReviewerID: oldUserID,
+		Reason:             newReviewer.Reason,
+	}))
+}
+
+// notifyUnassigned emits reviewer.unassigned for a reviewer removed from a pull request without replacement.
+func (s *PRService) notifyUnassigned(ctx context.Context, prID, userID, reason string) error {
+	return s.notifier.Notify(ctx, domain.NewEvent(domain.EventReviewerUnassigned, domain.ReviewerEventData{
+		PullRequestID: prID,
+		ReviewerID:    userID,
+		Reason:        reason,
+	}))
+}
+
+// checkOpen returns ErrPRMerged for merged and ErrPRNotOpen for draft or closed pull requests.
+func checkOpen(pr *domain.PullRequest) error {
+	switch pr.Status {
+	case domain.PRStatusOpen:
+		return nil
+	case domain.PRStatusMerged:
+		return domain.ErrPRMerged
+	default:
+		return fmt.Errorf("%w: status %s", domain.ErrPRNotOpen, pr.Status)
+	}
 }
 
 // isAssigned checks if a user is in the reviewers list.
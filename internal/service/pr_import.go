@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	. "github.com/go-ozzo/ozzo-validation"
+)
+
+// ImportEntry is a pull request read from an import file.
+// Err is set when the entry couldn't be parsed.
+type ImportEntry struct {
+	Line int // номер строки NDJSON или элемента массива, с 1
+	PR   *domain.PullRequest
+	Err  error
+}
+
+// ImportError describes an entry that wasn't imported.
+type ImportError struct {
+	Line          int
+	PullRequestID string
+	Err           error
+}
+
+type ImportResult struct {
+	Imported   int
+	Failed     int
+	RolledBack bool // atomic import was cancelled, nothing was imported
+	Errors     []ImportError
+}
+
+// ImportPRs stores pull requests migrated from another tool as is: explicit status,
+// timestamps and reviewers, no automatic assignment. Every entry is validated and
+// errors are reported per entry. Non-atomic imports store valid entries one by one;
+// atomic imports store either all entries in one transaction or none.
+func (s *PRService) ImportPRs(ctx context.Context, entries []ImportEntry, atomic bool, actorID string) (*ImportResult, error) {
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	result := &ImportResult{Errors: []ImportError{}}
+	seen := make(map[string]bool, len(entries))
+
+	fail := func(entry ImportEntry, err error) {
+		importErr := ImportError{Line: entry.Line, Err: err}
+		if entry.PR != nil {
+			importErr.PullRequestID = entry.PR.PullRequestID
+		}
+		result.Errors = append(result.Errors, importErr)
+		result.Failed++
+	}
+
+	valid := make([]ImportEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Err != nil {
+			fail(entry, entry.Err)
+			continue
+		}
+
+		if err := s.prepareImport(ctx, entry.PR, seen); err != nil {
+			fail(entry, err)
+			continue
+		}
+
+		if !atomic {
+			if err := s.prRepo.Import(ctx, entry.PR, actorID); err != nil {
+				s.logger.Error("failed to import pr",
+					"pr_id", entry.PR.PullRequestID,
+					"line", entry.Line,
+					"error", err,
+				)
+				fail(entry, fmt.Errorf("import pr: %w", err))
+				continue
+			}
+			result.Imported++
+			continue
+		}
+
+		valid = append(valid, entry)
+	}
+
+	if atomic {
+		if result.Failed > 0 {
+			result.RolledBack = true
+		} else {
+			err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+				for _, entry := range valid {
+					if err := s.prRepo.Import(ctx, entry.PR, actorID); err != nil {
+						return fmt.Errorf("import pr %s (line %d): %w", entry.PR.PullRequestID, entry.Line, err)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			result.Imported = len(valid)
+		}
+	}
+
+	s.logger.Info("prs imported",
+		"imported", result.Imported,
+		"failed", result.Failed,
+		"atomic", atomic,
+		"rolled_back", result.RolledBack,
+		"actor_id", actorID,
+	)
+
+	return result, nil
+}
+
+// prepareImport validates an imported pull request against the current state and fills defaults:
+// OPEN status, created_at of finished pull requests, PENDING decisions, reviewer's current team,
+// assigned_at and the imported reason.
+// seen collects IDs of the batch to catch duplicates within it.
+func (s *PRService) prepareImport(ctx context.Context, pr *domain.PullRequest, seen map[string]bool) error {
+	if pr.Status == "" {
+		pr.Status = domain.PRStatusOpen
+	}
+
+	// Reviewers may be given as plain IDs only
+	if len(pr.Reviewers) == 0 {
+		for _, reviewerID := range pr.AssignedReviewers {
+			pr.Reviewers = append(pr.Reviewers, domain.Reviewer{UserID: reviewerID})
+		}
+	}
+
+	// Finished pull requests without created_at would otherwise be created now,
+	// after they were merged or closed
+	if pr.CreatedAt == nil {
+		switch {
+		case pr.MergedAt != nil:
+			pr.CreatedAt = pr.MergedAt
+		case pr.ClosedAt != nil:
+			pr.CreatedAt = pr.ClosedAt
+		}
+	}
+
+	for i := range pr.Reviewers {
+		if pr.Reviewers[i].AssignedAt == nil {
+			pr.Reviewers[i].AssignedAt = pr.CreatedAt
+		}
+		if pr.Reviewers[i].Decision == "" {
+			pr.Reviewers[i].Decision = domain.ReviewDecisionPending
+		}
+		if pr.Reviewers[i].Reason == "" {
+			pr.Reviewers[i].Reason = domain.ReasonImported
+		}
+	}
+	pr.AssignedReviewers = reviewerIDs(pr.Reviewers)
+
+	if err := validateImport(pr); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	if seen[pr.PullRequestID] {
+		return fmt.Errorf("%w: duplicate %s in import", domain.ErrPRExists, pr.PullRequestID)
+	}
+	seen[pr.PullRequestID] = true
+
+	exists, err := s.prRepo.Exists(ctx, pr.PullRequestID)
+	if err != nil {
+		return fmt.Errorf("check pr exists: %w", err)
+	}
+	if exists {
+		return domain.ErrPRExists
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, pr.AuthorID); err != nil {
+		return fmt.Errorf("get author %s: %w", pr.AuthorID, err)
+	}
+
+	// Reviewers are taken as they were: inactive users are allowed
+	for i := range pr.Reviewers {
+		reviewer, err := s.userRepo.GetByID(ctx, pr.Reviewers[i].UserID)
+		if err != nil {
+			return fmt.Errorf("get reviewer %s: %w", pr.Reviewers[i].UserID, err)
+		}
+		if pr.Reviewers[i].TeamName == "" {
+			pr.Reviewers[i].TeamName = reviewer.TeamName
+		}
+	}
+
+	return nil
+}
+
+// validateImport checks an imported pull request for consistency of status,
+// timestamps and reviewers.
+func validateImport(pr *domain.PullRequest) error {
+	err := ValidateStruct(pr,
+		Field(&pr.PullRequestID,
+			Required,
+			Length(1, 255),
+		),
+		Field(&pr.PullRequestName,
+			Required,
+			Length(1, 255),
+		),
+		Field(&pr.AuthorID,
+			Required,
+			Length(1, 255),
+		),
+		Field(&pr.Reviewers,
+			Length(0, maxReviewersLimit),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	if !pr.Status.IsValid() {
+		return fmt.Errorf("unknown status %q", pr.Status)
+	}
+
+	switch pr.Status {
+	case domain.PRStatusMerged:
+		if pr.MergedAt == nil || pr.ClosedAt != nil {
+			return errors.New("merged pull request needs merged_at and no closed_at")
+		}
+	case domain.PRStatusClosed:
+		if pr.ClosedAt == nil || pr.MergedAt != nil {
+			return errors.New("closed pull request needs closed_at and no merged_at")
+		}
+	default:
+		if pr.MergedAt != nil || pr.ClosedAt != nil {
+			return fmt.Errorf("%s pull request can't have merged_at or closed_at", pr.Status)
+		}
+	}
+
+	if pr.Status == domain.PRStatusDraft && len(pr.Reviewers) > 0 {
+		return errors.New("draft pull request can't have reviewers")
+	}
+
+	if pr.CreatedAt != nil {
+		if pr.MergedAt != nil && pr.MergedAt.Before(*pr.CreatedAt) {
+			return errors.New("merged_at is before created_at")
+		}
+		if pr.ClosedAt != nil && pr.ClosedAt.Before(*pr.CreatedAt) {
+			return errors.New("closed_at is before created_at")
+		}
+	}
+
+	reviewers := make(map[string]bool, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		switch {
+		case reviewer.UserID == "":
+			return errors.New("reviewer user_id is required")
+		case reviewer.UserID == pr.AuthorID:
+			return fmt.Errorf("author %s can't review their own pull request", pr.AuthorID)
+		case reviewers[reviewer.UserID]:
+			return fmt.Errorf("duplicate reviewer %s", reviewer.UserID)
+		case !reviewer.Decision.IsValid():
+			return fmt.Errorf("unknown decision %q of reviewer %s", reviewer.Decision, reviewer.UserID)
+		case reviewer.Decision == domain.ReviewDecisionPending && reviewer.DecidedAt != nil:
+			return fmt.Errorf("pending reviewer %s can't have decided_at", reviewer.UserID)
+		}
+		reviewers[reviewer.UserID] = true
+	}
+
+	return nil
+}
+
+// ExportPRs streams pull requests matching the filter to fn in the import format.
+func (s *PRService) ExportPRs(ctx context.Context, filter domain.PRFilter, fn func(*domain.PullRequest) error) error {
+	if err := s.validateFilter(ctx, filter); err != nil {
+		return err
+	}
+
+	exported := 0
+	err := s.prRepo.Export(ctx, filter, func(pr *domain.PullRequest) error {
+		exported++
+		return fn(pr)
+	})
+	if err != nil {
+		return fmt.Errorf("export prs: %w", err)
+	}
+
+	s.logger.Info("prs exported",
+		"status", filter.Status,
+		"team_name", filter.TeamName,
+		"count", exported,
+	)
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+)
+
+// MarkReady moves a draft pull request to OPEN and assigns reviewers
+// the same way CreatePR does for non-draft pull requests.
+func (s *PRService) MarkReady(ctx context.Context, prID, actorID string, files []string) (*CreatePRResponse, error) {
+	pr, err := s.getForTransition(ctx, prID, domain.PRStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status != domain.PRStatusDraft {
+		return nil, fmt.Errorf("%w: %s is not a draft", domain.ErrInvalidTransition, prID)
+	}
+
+	return s.open(ctx, pr, actorID, files)
+}
+
+// ClosePR closes a draft or open pull request without merging.
+// Its reviewers stay recorded but no longer count as open reviews;
+// the closing is recorded in their history with the actor and published as pr.closed.
+func (s *PRService) ClosePR(ctx context.Context, prID, actorID string) (*domain.PullRequest, error) {
+	pr, err := s.getForTransition(ctx, prID, domain.PRStatusClosed)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	var closed *domain.PullRequest
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.Close(ctx, prID, actorID); err != nil {
+			return fmt.Errorf("close pr: %w", err)
+		}
+
+		var err error
+		closed, err = s.prRepo.GetByID(ctx, prID)
+		if err != nil {
+			return fmt.Errorf("get closed pr: %w", err)
+		}
+
+		return s.notifier.Notify(ctx, domain.NewEvent(domain.EventPRClosed, domain.PREventData{PR: closed}))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("pr closed",
+		"pr_id", prID,
+		"previous_status", pr.Status,
+		"reviewers_count", len(closed.AssignedReviewers),
+		"actor_id", actorID,
+	)
+
+	return closed, nil
+}
+
+// ReopenPR moves a closed pull request back to OPEN.
+// Previous reviewers are kept while they are still eligible; pending reviewers who were
+// deactivated, are unavailable or reached their open review cap are replaced.
+// Reviewers are assigned from scratch only if there are none.
+func (s *PRService) ReopenPR(ctx context.Context, prID, actorID string) (*CreatePRResponse, error) {
+	pr, err := s.getForTransition(ctx, prID, domain.PRStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status != domain.PRStatusClosed {
+		return nil, fmt.Errorf("%w: %s is not closed", domain.ErrInvalidTransition, prID)
+	}
+
+	return s.open(ctx, pr, actorID, nil)
+}
+
+// open moves a draft or closed pull request to OPEN, assigning reviewers if it has none
+// and replacing kept reviewers who can no longer review.
+func (s *PRService) open(ctx context.Context, pr *domain.PullRequest, actorID string, files []string) (*CreatePRResponse, error) {
+	reviewers := []domain.Reviewer{}
+	swaps := []reviewerSwap{}
+	var warnings []AssignmentWarning
+
+	if len(pr.Reviewers) == 0 {
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("get author: %w", err)
+		}
+
+		reviewers, warnings, err = s.assignReviewers(ctx, author, files)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		swaps, err = s.planSwaps(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if actorID == "" {
+		actorID = domain.SystemActor
+	}
+
+	var opened *domain.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.prRepo.Open(ctx, pr.PullRequestID, reviewers, actorID); err != nil {
+			return fmt.Errorf("open pr: %w", err)
+		}
+
+		if err := s.applySwaps(ctx, pr.PullRequestID, swaps, actorID); err != nil {
+			return err
+		}
+
+		var err error
+		opened, err = s.prRepo.GetByID(ctx, pr.PullRequestID)
+		if err != nil {
+			return fmt.Errorf("get opened pr: %w", err)
+		}
+
+		return s.notifyAssigned(ctx, pr.PullRequestID, reviewers)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("pr opened",
+		"pr_id", pr.PullRequestID,
+		"previous_status", pr.Status,
+		"reviewers_count", len(opened.AssignedReviewers),
+		"replaced_count", len(swaps),
+		"actor_id", actorID,
+		"warnings", warnings,
+	)
+
+	return &CreatePRResponse{PR: opened, Warnings: warnings}, nil
+}
+
+// reviewerSwap is a kept reviewer to replace when a pull request is reopened.
+// Nil replacement means nobody could take over and the reviewer is unassigned.
+type reviewerSwap struct {
+	oldUserID   string
+	reason      string
+	replacement *domain.Reviewer
+}
+
+// planSwaps finds pending reviewers of pr who are inactive, unavailable or at their
+// open review cap and picks a replacement for each of them the way ReassignReviewer does.
+func (s *PRService) planSwaps(ctx context.Context, pr *domain.PullRequest) ([]reviewerSwap, error) {
+	pending := make([]string, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Decision == domain.ReviewDecisionPending {
+			pending = append(pending, reviewer.UserID)
+		}
+	}
+
+	if len(pending) == 0 {
+		return []reviewerSwap{}, nil
+	}
+
+	candidates, err := s.userRepo.GetActiveCandidates(ctx, pending)
+	if err != nil {
+		return nil, fmt.Errorf("get reviewers: %w", err)
+	}
+
+	eligible := make(map[string]*domain.ReviewerCandidate, len(candidates))
+	for _, candidate := range candidates {
+		eligible[candidate.UserID] = candidate
+	}
+
+	// Replacements must not pick each other
+	current := *pr
+	current.AssignedReviewers = append([]string{}, pr.AssignedReviewers...)
+
+	swaps := []reviewerSwap{}
+	for _, userID := range pending {
+		reason, err := s.ineligibility(ctx, userID, eligible[userID])
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			continue
+		}
+
+		swap := reviewerSwap{oldUserID: userID, reason: reason}
+		replacement, err := s.pickReplacement(ctx, &current, userID)
+		switch {
+		case errors.Is(err, domain.ErrNoCandidate):
+			// Unassigned without replacement
+		case err != nil:
+			return nil, err
+		default:
+			replacement.Reason = reason
+			swap.replacement = &replacement
+			current.AssignedReviewers = append(current.AssignedReviewers, replacement.UserID)
+		}
+
+		swaps = append(swaps, swap)
+	}
+
+	return swaps, nil
+}
+
+// ineligibility returns the reason a kept reviewer can't keep reviewing, or empty
+// string if they can. candidate is nil for inactive and unavailable users.
+func (s *PRService) ineligibility(ctx context.Context, userID string, candidate *domain.ReviewerCandidate) (string, error) {
+	if candidate != nil {
+		if candidate.AtCapacity() {
+			return domain.ReasonAtCapacity, nil
+		}
+		return "", nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("get reviewer: %w", err)
+	}
+
+	if !user.IsActive {
+		return domain.ReasonDeactivated, nil
+	}
+	return domain.ReasonUnavailable, nil
+}
+
+// applySwaps replaces or unassigns reviewers of an open pull request as planned by planSwaps.
+func (s *PRService) applySwaps(ctx context.Context, prID string, swaps []reviewerSwap, actorID string) error {
+	for _, swap := range swaps {
+		if swap.replacement == nil {
+			if err := s.prRepo.RemoveReviewer(ctx, prID, swap.oldUserID, actorID, swap.reason); err != nil {
+				return fmt.Errorf("remove reviewer: %w", err)
+			}
+			if err := s.notifyUnassigned(ctx, prID, swap.oldUserID, swap.reason); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.prRepo.ReplaceReviewer(ctx, prID, swap.oldUserID, *swap.replacement, actorID); err != nil {
+			return fmt.Errorf("replace reviewer: %w", err)
+		}
+
+		if err := s.notifyReassigned(ctx, prID, swap.oldUserID, *swap.replacement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getForTransition loads a pull request and validates it may move to the next status.
+func (s *PRService) getForTransition(ctx context.Context, prID string, next domain.PRStatus) (*domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get pr: %w", err)
+	}
+
+	if !pr.Status.CanTransitionTo(next) {
+		return nil, fmt.Errorf("%w: %s -> %s", domain.ErrInvalidTransition, pr.Status, next)
+	}
+
+	return pr, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+)
+
+// defaultPageSize is the page size used when the caller doesn't set a limit.
+const defaultPageSize = 50
+
+// maxPageSize is the upper bound for a page size.
+const maxPageSize = 200
+
+// ListPRs retrieves a page of pull requests matching the filter, newest first.
+// cursor is the next_cursor of the previous page, empty for the first page;
+// zero limit means default page size.
+func (s *PRService) ListPRs(ctx context.Context, filter domain.PRFilter, cursor string, limit int) (*domain.PRPage, error) {
+	limit, err := pageSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateFilter(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	var after *domain.Cursor
+	if cursor != "" {
+		after, err = domain.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// One extra row tells whether there is a next page
+	prs, err := s.prRepo.List(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list prs: %w", err)
+	}
+
+	page := &domain.PRPage{PullRequests: prs}
+	if len(prs) > limit {
+		page.PullRequests = prs[:limit]
+		last := page.PullRequests[limit-1]
+		page.NextCursor = domain.Cursor{Time: *last.CreatedAt, ID: last.PullRequestID}.Encode()
+	}
+
+	s.logger.Info("prs listed",
+		"count", len(page.PullRequests),
+		"has_more", page.NextCursor != "",
+	)
+
+	return page, nil
+}
+
+// pageSize applies the default page size and checks the bounds.
+func pageSize(limit int) (int, error) {
+	if limit == 0 {
+		return defaultPageSize, nil
+	}
+
+	if limit < 0 || limit > maxPageSize {
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidRequest, maxPageSize)
+	}
+
+	return limit, nil
+}
+
+// validateFilter checks filter values and that the filtered team exists.
+func (s *PRService) validateFilter(ctx context.Context, filter domain.PRFilter) error {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", domain.ErrInvalidRequest, filter.Status)
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return fmt.Errorf("%w: created_from must be before created_to", domain.ErrInvalidRequest)
+	}
+
+	if filter.MergedFrom != nil && filter.MergedTo != nil && !filter.MergedFrom.Before(*filter.MergedTo) {
+		return fmt.Errorf("%w: merged_from must be before merged_to", domain.ErrInvalidRequest)
+	}
+
+	if filter.TeamName != "" {
+		exists, err := s.teamRepo.TeamExists(ctx, filter.TeamName)
+		if err != nil {
+			return fmt.Errorf("check team exists: %w", err)
+		}
+		if !exists {
+			return domain.ErrTeamNotFound
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+)
+
+// GetOverdue lists pending reviews of open pull requests held longer than
+// the review SLA of the author's team. Empty teamName means all teams.
+func (s *PRService) GetOverdue(ctx context.Context, teamName string) ([]*domain.OverdueReview, error) {
+	overdue, err := s.prRepo.GetOverdue(ctx, teamName, false)
+	if err != nil {
+		return nil, fmt.Errorf("get overdue reviews: %w", err)
+	}
+
+	s.logger.Info("retrieved overdue reviews",
+		"team_name", teamName,
+		"count", len(overdue),
+	)
+
+	return overdue, nil
+}
+
+// EscalateOverdue reassigns overdue reviews of teams with escalate_overdue enabled
+// through ReassignReviewer. Reviews without a replacement are left as they are.
+// Returns the number of reassigned reviews.
+func (s *PRService) EscalateOverdue(ctx context.Context) (int, error) {
+	overdue, err := s.prRepo.GetOverdue(ctx, "", true)
+	if err != nil {
+		return 0, fmt.Errorf("get overdue reviews: %w", err)
+	}
+
+	reassigned := 0
+	for _, review := range overdue {
+		_, newReviewerID, err := s.ReassignReviewer(ctx, review.PullRequestID, review.ReviewerID,
+			domain.SystemActor, domain.ReasonSLAExpired)
+
+		switch {
+		case err == nil:
+			reassigned++
+			s.logger.Info("overdue review escalated",
+				"pr_id", review.PullRequestID,
+				"old_reviewer", review.ReviewerID,
+				"new_reviewer", newReviewerID,
+				"due_at", review.DueAt,
+			)
+		case errors.Is(err, domain.ErrNoCandidate),
+			errors.Is(err, domain.ErrNotAssigned),
+			errors.Is(err, domain.ErrPRNotOpen),
+			errors.Is(err, domain.ErrPRMerged):
+			// Nobody to hand the review over to, or the PR changed meanwhile
+			s.logger.Warn("overdue review not escalated",
+				"pr_id", review.PullRequestID,
+				"reviewer", review.ReviewerID,
+				"reason", err.Error(),
+			)
+		default:
+			return reassigned, fmt.Errorf("escalate review of %s on %s: %w",
+				review.ReviewerID, review.PullRequestID, err)
+		}
+	}
+
+	return reassigned, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"time"
+)
+
+// CodeHostClient changes review requests on pull requests of a git hosting.
+// Errors having a Retryable() bool method that returns false aren't retried;
+// any other error is.
+type CodeHostClient interface {
+	RequestReviewers(ctx context.Context, repository string, number int, logins []string) error
+	RemoveReviewers(ctx context.Context, repository string, number int, logins []string) error
+}
+
+type ReviewerSyncConfig struct {
+	BatchSize   int           // задач за один проход воркера
+	MaxAttempts int           // после стольких неудачных попыток задача уходит в dead letters
+	RetryBase   time.Duration // задержка перед второй попыткой, дальше удваивается
+	RetryMax    time.Duration // верхняя граница задержки
+	Lease       time.Duration // через сколько повторить задачу, если воркер упал
+}
+
+type ReviewerSyncService struct {
+	repo         repository.ReviewerSyncRepository
+	identityRepo repository.IdentityRepository
+	clients      map[domain.Provider]CodeHostClient
+	config       ReviewerSyncConfig
+	logger       *logger.Logger
+}
+
+// NewReviewerSyncService creates the service. Pull requests of providers
+// without a client in clients are not synced.
+func NewReviewerSyncService(
+	repo repository.ReviewerSyncRepository,
+	identityRepo repository.IdentityRepository,
+	clients map[domain.Provider]CodeHostClient,
+	config ReviewerSyncConfig,
+	logger *logger.Logger,
+) *ReviewerSyncService {
+	return &ReviewerSyncService{
+		repo:         repo,
+		identityRepo: identityRepo,
+		clients:      clients,
+		config:       config,
+		logger:       logger.Component("service/reviewer_sync"),
+	}
+}
+
+// HandleEvent queues copying of a reviewer.assigned, reviewer.reassigned or
+// reviewer.unassigned event to the git hosting of the pull request. Events of pull
+// requests created through the API or hosted where no client is configured are skipped.
+// Subscribed to the in-process publisher, it runs within the relay transaction.
+func (s *ReviewerSyncService) HandleEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	switch event.Type {
+	case domain.EventReviewerAssigned, domain.EventReviewerReassigned, domain.EventReviewerUnassigned:
+	default:
+		return nil
+	}
+
+	var envelope struct {
+		Data domain.ReviewerEventData `json:"data"`
+	}
+	if err := json.Unmarshal(event.Payload, &envelope); err != nil {
+		return fmt.Errorf("decode event %d: %w", event.EventID, err)
+	}
+
+	ref, ok := domain.ParseExternalPRRef(envelope.Data.PullRequestID)
+	if !ok || s.clients[ref.Provider] == nil {
+		return nil
+	}
+
+	task := &domain.ReviewerSyncTask{
+		PullRequestID:    envelope.Data.PullRequestID,
+		AddReviewerID:    envelope.Data.ReviewerID,
+		RemoveReviewerID: envelope.Data.PreviousReviewerID,
+	}
+	if event.Type == domain.EventReviewerUnassigned {
+		task.AddReviewerID, task.RemoveReviewerID = "", envelope.Data.ReviewerID
+	}
+
+	err := s.repo.Enqueue(ctx, task)
+	if err != nil {
+		return fmt.Errorf("queue reviewer sync: %w", err)
+	}
+
+	return nil
+}
+
+// Sync copies a batch of due reviewer changes to git hostings. Temporary failures are
+// retried with exponential backoff until MaxAttempts; permanent ones and tasks out of
+// attempts become dead letters. Returns the number of synced tasks.
+func (s *ReviewerSyncService) Sync(ctx context.Context) (int, error) {
+	tasks, err := s.repo.ClaimDue(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim sync tasks: %w", err)
+	}
+
+	synced := 0
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			// Unprocessed tasks are retried when their lease expires
+			return synced, ctx.Err()
+		}
+
+		syncErr := s.syncTask(ctx, task)
+		if syncErr == nil {
+			if err := s.repo.Complete(ctx, task.TaskID); err != nil {
+				return synced, err
+			}
+			synced++
+			continue
+		}
+
+		if !isRetryable(syncErr) || task.Attempts >= s.config.MaxAttempts {
+			s.logger.Error("reviewer sync failed for good",
+				"task_id", task.TaskID,
+				"pr_id", task.PullRequestID,
+				"attempt", task.Attempts,
+				"error", syncErr,
+			)
+
+			if err := s.repo.DeadLetter(ctx, task.TaskID, syncErr.Error()); err != nil {
+				return synced, err
+			}
+			continue
+		}
+
+		next := time.Now().Add(retryDelay(s.config.RetryBase, s.config.RetryMax, task.Attempts))
+
+		s.logger.Warn("reviewer sync failed",
+			"task_id", task.TaskID,
+			"pr_id", task.PullRequestID,
+			"attempt", task.Attempts,
+			"error", syncErr,
+			"next_attempt_at", next,
+		)
+
+		if err := s.repo.Retry(ctx, task.TaskID, syncErr.Error(), next); err != nil {
+			return synced, err
+		}
+	}
+
+	if len(tasks) > 0 {
+		s.logger.Info("reviewer sync tasks processed",
+			"claimed", len(tasks),
+			"synced", synced,
+		)
+	}
+
+	return synced, nil
+}
+
+// syncTask cancels the review request of the replaced reviewer, then requests the new one.
+func (s *ReviewerSyncService) syncTask(ctx context.Context, task *domain.ReviewerSyncTask) error {
+	ref, ok := domain.ParseExternalPRRef(task.PullRequestID)
+	client := s.clients[ref.Provider]
+	if !ok || client == nil {
+		return permanentError{fmt.Errorf("no code host client for %s", task.PullRequestID)}
+	}
+
+	if task.RemoveReviewerID != "" {
+		login, err := s.login(ctx, ref.Provider, task.RemoveReviewerID)
+		if err != nil {
+			return err
+		}
+		if err := client.RemoveReviewers(ctx, ref.Repository, ref.Number, []string{login}); err != nil {
+			return fmt.Errorf("remove reviewer %s: %w", login, err)
+		}
+	}
+
+	if task.AddReviewerID != "" {
+		login, err := s.login(ctx, ref.Provider, task.AddReviewerID)
+		if err != nil {
+			return err
+		}
+		if err := client.RequestReviewers(ctx, ref.Repository, ref.Number, []string{login}); err != nil {
+			return fmt.Errorf("request reviewer %s: %w", login, err)
+		}
+	}
+
+	return nil
+}
+
+// login finds the login of a user on the provider. Users without one can't be synced.
+func (s *ReviewerSyncService) login(ctx context.Context, provider domain.Provider, userID string) (string, error) {
+	login, err := s.identityRepo.LoginOf(ctx, provider, userID)
+	if errors.Is(err, domain.ErrIdentityNotFound) {
+		return "", permanentError{err}
+	}
+	return login, err
+}
+
+// ListDeadLetters retrieves the latest tasks that failed for good.
+// Zero limit means default page size.
+func (s *ReviewerSyncService) ListDeadLetters(ctx context.Context, limit int) ([]*domain.ReviewerSyncTask, error) {
+	limit, err := pageSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.ListDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// RetryDeadLetter queues a dead letter again with attempts reset,
+// e.g. after the missing login was mapped.
+func (s *ReviewerSyncService) RetryDeadLetter(ctx context.Context, taskID int64) error {
+	if err := s.repo.Requeue(ctx, taskID); err != nil {
+		return fmt.Errorf("requeue sync task: %w", err)
+	}
+
+	s.logger.Info("reviewer sync task requeued", "task_id", taskID)
+
+	return nil
+}
+
+// permanentError marks failures that repeating won't fix.
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+func (e permanentError) Retryable() bool {
+	return false
+}
+
+// isRetryable reports whether a failed call may succeed if repeated.
+// Errors that don't tell are assumed temporary.
+func isRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	return true
+}
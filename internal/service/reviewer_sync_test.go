@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/github"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSyncRepository hands out the given tasks and records what happened to them.
+type fakeSyncRepository struct {
+	tasks       []*domain.ReviewerSyncTask
+	completed   []int64
+	retried     map[int64]time.Time
+	deadLetters map[int64]string
+}
+
+func newFakeSyncRepository(tasks ...*domain.ReviewerSyncTask) *fakeSyncRepository {
+	return &fakeSyncRepository{
+		tasks:       tasks,
+		retried:     make(map[int64]time.Time),
+		deadLetters: make(map[int64]string),
+	}
+}
+
+func (r *fakeSyncRepository) Enqueue(ctx context.Context, task *domain.ReviewerSyncTask) error {
+	r.tasks = append(r.tasks, task)
+	return nil
+}
+
+func (r *fakeSyncRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*domain.ReviewerSyncTask, error) {
+	tasks := r.tasks
+	r.tasks = nil
+	return tasks, nil
+}
+
+func (r *fakeSyncRepository) Complete(ctx context.Context, taskID int64) error {
+	r.completed = append(r.completed, taskID)
+	return nil
+}
+
+func (r *fakeSyncRepository) Retry(ctx context.Context, taskID int64, lastError string, nextAttemptAt time.Time) error {
+	r.retried[taskID] = nextAttemptAt
+	return nil
+}
+
+func (r *fakeSyncRepository) DeadLetter(ctx context.Context, taskID int64, lastError string) error {
+	r.deadLetters[taskID] = lastError
+	return nil
+}
+
+func (r *fakeSyncRepository) ListDeadLetters(ctx context.Context, limit int) ([]*domain.ReviewerSyncTask, error) {
+	return nil, nil
+}
+
+func (r *fakeSyncRepository) Requeue(ctx context.Context, taskID int64) error {
+	return nil
+}
+
+// fakeIdentityRepository maps user IDs to GitHub logins.
+type fakeIdentityRepository map[string]string
+
+func (r fakeIdentityRepository) SaveIdentity(ctx context.Context, identity *domain.ExternalIdentity) (*domain.ExternalIdentity, error) {
+	return identity, nil
+}
+
+func (r fakeIdentityRepository) ListIdentities(ctx context.Context, provider domain.Provider) ([]*domain.ExternalIdentity, error) {
+	return nil, nil
+}
+
+func (r fakeIdentityRepository) DeleteIdentity(ctx context.Context, provider domain.Provider, login string) error {
+	return nil
+}
+
+func (r fakeIdentityRepository) ResolveUser(ctx context.Context, provider domain.Provider, login string) (string, error) {
+	return "", domain.ErrIdentityNotFound
+}
+
+func (r fakeIdentityRepository) LoginOf(ctx context.Context, provider domain.Provider, userID string) (string, error) {
+	login, ok := r[userID]
+	if !ok {
+		return "", domain.ErrIdentityNotFound
+	}
+	return login, nil
+}
+
+type recordedRequest struct {
+	Method    string
+	Path      string
+	Reviewers []string
+}
+
+// githubServer answers review request calls with status and records them.
+type githubServer struct {
+	*httptest.Server
+	status int
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func newGitHubServer(t *testing.T, status int) *githubServer {
+	s := &githubServer{status: status}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, recordedRequest{Method: r.Method, Path: r.URL.Path, Reviewers: body.Reviewers})
+		s.mu.Unlock()
+
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(`{"message":"` + http.StatusText(s.status) + `"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+var testSyncConfig = ReviewerSyncConfig{
+	BatchSize:   10,
+	MaxAttempts: 3,
+	RetryBase:   time.Minute,
+	RetryMax:    10 * time.Minute,
+	Lease:       time.Minute,
+}
+
+func newTestReviewerSyncService(t *testing.T, repo *fakeSyncRepository, server *githubServer) *ReviewerSyncService {
+	t.Helper()
+
+	log, err := logger.New(&logger.Config{Level: "error", Format: "json"})
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+
+	clients := map[domain.Provider]CodeHostClient{
+		domain.ProviderGitHub: github.NewClient(server.URL, "token", time.Second),
+	}
+	identities := fakeIdentityRepository{"u1": "alice", "u2": "bob"}
+
+	return NewReviewerSyncService(repo, identities, clients, testSyncConfig, log)
+}
+
+func TestReviewerSyncSuccess(t *testing.T) {
+	server := newGitHubServer(t, http.StatusCreated)
+	repo := newFakeSyncRepository(&domain.ReviewerSyncTask{
+		TaskID:           1,
+		PullRequestID:    "github:acme/backend#42",
+		AddReviewerID:    "u2",
+		RemoveReviewerID: "u1",
+		Attempts:         1,
+	})
+	s := newTestReviewerSyncService(t, repo, server)
+
+	synced, err := s.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if synced != 1 {
+		t.Errorf("synced = %d, want 1", synced)
+	}
+	if !reflect.DeepEqual(repo.completed, []int64{1}) {
+		t.Errorf("completed = %v, want [1]", repo.completed)
+	}
+
+	// The replaced reviewer is removed before the new one is requested
+	want := []recordedRequest{
+		{Method: http.MethodDelete, Path: "/repos/acme/backend/pulls/42/requested_reviewers", Reviewers: []string{"alice"}},
+		{Method: http.MethodPost, Path: "/repos/acme/backend/pulls/42/requested_reviewers", Reviewers: []string{"bob"}},
+	}
+	if !reflect.DeepEqual(server.requests, want) {
+		t.Errorf("requests = %+v, want %+v", server.requests, want)
+	}
+}
+
+func TestReviewerSyncRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		delay    time.Duration
+	}{
+		{name: "server error", status: http.StatusInternalServerError, attempts: 1, delay: time.Minute},
+		{name: "too many requests", status: http.StatusTooManyRequests, attempts: 2, delay: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newGitHubServer(t, tt.status)
+			repo := newFakeSyncRepository(&domain.ReviewerSyncTask{
+				TaskID:        1,
+				PullRequestID: "github:acme/backend#42",
+				AddReviewerID: "u2",
+				Attempts:      tt.attempts,
+			})
+			s := newTestReviewerSyncService(t, repo, server)
+
+			before := time.Now()
+			synced, err := s.Sync(context.Background())
+			after := time.Now()
+			if err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+			if synced != 0 || len(repo.completed) != 0 || len(repo.deadLetters) != 0 {
+				t.Fatalf("synced = %d, completed = %v, dead letters = %v, want retry only", synced, repo.completed, repo.deadLetters)
+			}
+
+			next, ok := repo.retried[1]
+			if !ok {
+				t.Fatal("task is not retried")
+			}
+			if next.Before(before.Add(tt.delay)) || next.After(after.Add(tt.delay)) {
+				t.Errorf("next attempt in %v, want %v", next.Sub(before), tt.delay)
+			}
+		})
+	}
+}
+
+func TestReviewerSyncDeadLetter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		task   *domain.ReviewerSyncTask
+	}{
+		{
+			name:   "client error",
+			status: http.StatusUnprocessableEntity,
+			task:   &domain.ReviewerSyncTask{TaskID: 1, PullRequestID: "github:acme/backend#42", AddReviewerID: "u2", Attempts: 1},
+		},
+		{
+			name:   "out of attempts",
+			status: http.StatusInternalServerError,
+			task:   &domain.ReviewerSyncTask{TaskID: 1, PullRequestID: "github:acme/backend#42", AddReviewerID: "u2", Attempts: 3},
+		},
+		{
+			name:   "unknown login",
+			status: http.StatusCreated,
+			task:   &domain.ReviewerSyncTask{TaskID: 1, PullRequestID: "github:acme/backend#42", AddReviewerID: "u3", Attempts: 1},
+		},
+		{
+			name:   "no client for provider",
+			status: http.StatusCreated,
+			task:   &domain.ReviewerSyncTask{TaskID: 1, PullRequestID: "gitlab:acme/backend!7", AddReviewerID: "u2", Attempts: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newGitHubServer(t, tt.status)
+			repo := newFakeSyncRepository(tt.task)
+			s := newTestReviewerSyncService(t, repo, server)
+
+			if _, err := s.Sync(context.Background()); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+			if len(repo.completed) != 0 || len(repo.retried) != 0 {
+				t.Fatalf("completed = %v, retried = %v, want dead letter only", repo.completed, repo.retried)
+			}
+			if _, ok := repo.deadLetters[1]; !ok {
+				t.Error("task is not dead-lettered")
+			}
+		})
+	}
+}
+
+func TestReviewerSyncRemoveFailure(t *testing.T) {
+	server := newGitHubServer(t, http.StatusBadGateway)
+	repo := newFakeSyncRepository(&domain.ReviewerSyncTask{
+		TaskID:           1,
+		PullRequestID:    "github:acme/backend#42",
+		AddReviewerID:    "u2",
+		RemoveReviewerID: "u1",
+		Attempts:         1,
+	})
+	s := newTestReviewerSyncService(t, repo, server)
+
+	if _, err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// The new reviewer isn't requested until the replaced one is removed
+	if len(server.requests) != 1 || server.requests[0].Method != http.MethodDelete {
+		t.Errorf("requests = %+v, want a single DELETE", server.requests)
+	}
+	if _, ok := repo.retried[1]; !ok {
+		t.Error("task is not retried")
+	}
+}
+
+func TestReviewerSyncHandleEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType domain.EventType
+		data      domain.ReviewerEventData
+		want      []*domain.ReviewerSyncTask
+	}{
+		{
+			name:      "assigned",
+			eventType: domain.EventReviewerAssigned,
+			data:      domain.ReviewerEventData{PullRequestID: "github:acme/backend#42", ReviewerID: "u2"},
+			want:      []*domain.ReviewerSyncTask{{PullRequestID: "github:acme/backend#42", AddReviewerID: "u2"}},
+		},
+		{
+			name:      "reassigned",
+			eventType: domain.EventReviewerReassigned,
+			data:      domain.ReviewerEventData{PullRequestID: "github:acme/backend#42", ReviewerID: "u2", PreviousReviewerID: "u1"},
+			want:      []*domain.ReviewerSyncTask{{PullRequestID: "github:acme/backend#42", AddReviewerID: "u2", RemoveReviewerID: "u1"}},
+		},
+		{
+			name:      "unassigned",
+			eventType: domain.EventReviewerUnassigned,
+			data:      domain.ReviewerEventData{PullRequestID: "github:acme/backend#42", ReviewerID: "u1"},
+			want:      []*domain.ReviewerSyncTask{{PullRequestID: "github:acme/backend#42", RemoveReviewerID: "u1"}},
+		},
+		{
+			name:      "pull request created through the API",
+			eventType: domain.EventReviewerAssigned,
+			data:      domain.ReviewerEventData{PullRequestID: "pr-1001", ReviewerID: "u2"},
+			want:      nil,
+		},
+		{
+			name:      "other event",
+			eventType: domain.EventPRClosed,
+			data:      domain.ReviewerEventData{PullRequestID: "github:acme/backend#42"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := json.Marshal(domain.NewEvent(tt.eventType, tt.data))
+			if err != nil {
+				t.Fatalf("encode event: %v", err)
+			}
+
+			repo := newFakeSyncRepository()
+			s := newTestReviewerSyncService(t, repo, newGitHubServer(t, http.StatusCreated))
+
+			if err := s.HandleEvent(context.Background(), &domain.OutboxEvent{EventID: 1, Type: tt.eventType, Payload: payload}); err != nil {
+				t.Fatalf("HandleEvent() error = %v", err)
+			}
+			if !reflect.DeepEqual(repo.tasks, tt.want) {
+				t.Errorf("queued tasks = %+v, want %+v", repo.tasks, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReviewerSelector picks up to count reviewers from a pool of candidates.
+// Implementations must not modify the candidates slice.
+type ReviewerSelector interface {
+	Select(ctx context.Context, pool CandidatePool, candidates []*domain.ReviewerCandidate, count int) ([]*domain.ReviewerCandidate, error)
+}
+
+// CandidatePool identifies the group of users candidates are taken from.
+// Stateful strategies keep a separate position for every pool they rotate over.
+type CandidatePool struct {
+	TeamName string
+	Name     string
+}
+
+// Pools of a team's candidates.
+const (
+	poolMembers = "members" // участники команды
+	poolOwners  = "owners"  // code owners по правилам команды
+)
+
+// outOfHours returns the pool of the same users picked outside their working hours.
+func (p CandidatePool) outOfHours() CandidatePool {
+	return CandidatePool{TeamName: p.TeamName, Name: p.Name + "/out_of_hours"}
+}
+
+// newSelectors builds the registry of built-in selection strategies.
+func newSelectors(
+	random *lockedRand,
+	teamRepo repository.TeamRepository,
+	tx repository.Transactor,
+) map[domain.ReviewerStrategy]ReviewerSelector {
+	return map[domain.ReviewerStrategy]ReviewerSelector{
+		domain.ReviewerStrategyRandom:         &randomSelector{random: random},
+		domain.ReviewerStrategyRoundRobin:     &roundRobinSelector{teamRepo: teamRepo, tx: tx},
+		domain.ReviewerStrategyLeastLoaded:    &leastLoadedSelector{random: random},
+		domain.ReviewerStrategyWeightedRandom: &weightedRandomSelector{random: random},
+	}
+}
+
+// randomSelector picks reviewers uniformly at random.
+// Uses Fisher-Yates shuffle for uniform distribution.
+type randomSelector struct {
+	random *lockedRand
+}
+
+func (s *randomSelector) Select(_ context.Context, _ CandidatePool, candidates []*domain.ReviewerCandidate, count int) ([]*domain.ReviewerCandidate, error) {
+	shuffled := s.random.shuffled(candidates)
+	return shuffled[:min(count, len(shuffled))], nil
+}
+
+// roundRobinSelector walks candidates in user_id order, continuing after
+// the last reviewer it picked from the same pool. Cursors are stored in
+// the database and locked while they're advanced, so they survive
+// restarts and are shared by all service instances.
+type roundRobinSelector struct {
+	teamRepo repository.TeamRepository
+	tx       repository.Transactor
+}
+
+func (s *roundRobinSelector) Select(ctx context.Context, pool CandidatePool, candidates []*domain.ReviewerCandidate, count int) ([]*domain.ReviewerCandidate, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []*domain.ReviewerCandidate{}, nil
+	}
+
+	ordered := make([]*domain.ReviewerCandidate, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	var selected []*domain.ReviewerCandidate
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		last, err := s.teamRepo.LockRoundRobinCursor(ctx, pool.TeamName, pool.Name)
+		if err != nil {
+			return fmt.Errorf("get round robin cursor: %w", err)
+		}
+
+		// Start from the first candidate after the previously picked one
+		start := sort.Search(len(ordered), func(i int) bool {
+			return ordered[i].UserID > last
+		})
+
+		count = min(count, len(ordered))
+		selected = make([]*domain.ReviewerCandidate, count)
+		for i := 0; i < count; i++ {
+			selected[i] = ordered[(start+i)%len(ordered)]
+		}
+
+		return s.teamRepo.SetRoundRobinCursor(ctx, pool.TeamName, pool.Name, selected[count-1].UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+// leastLoadedSelector picks reviewers with the fewest open reviews.
+// Ties are broken randomly.
+type leastLoadedSelector struct {
+	random *lockedRand
+}
+
+func (s *leastLoadedSelector) Select(_ context.Context, _ CandidatePool, candidates []*domain.ReviewerCandidate, count int) ([]*domain.ReviewerCandidate, error) {
+	shuffled := s.random.shuffled(candidates)
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return shuffled[i].OpenReviews < shuffled[j].OpenReviews
+	})
+	return shuffled[:min(count, len(shuffled))], nil
+}
+
+// weightedRandomSelector picks reviewers at random with probability
+// inversely proportional to their current load: 1 / (1 + open reviews).
+type weightedRandomSelector struct {
+	random *lockedRand
+}
+
+func (s *weightedRandomSelector) Select(_ context.Context, _ CandidatePool, candidates []*domain.ReviewerCandidate, count int) ([]*domain.ReviewerCandidate, error) {
+	pool := make([]*domain.ReviewerCandidate, len(candidates))
+	copy(pool, candidates)
+
+	count = min(count, len(pool))
+	selected := make([]*domain.ReviewerCandidate, 0, count)
+
+	// Sample without replacement
+	for len(selected) < count {
+		total := 0.0
+		for _, c := range pool {
+			total += weight(c)
+		}
+
+		point := s.random.Float64() * total
+		idx := len(pool) - 1
+		for i, c := range pool {
+			point -= weight(c)
+			if point < 0 {
+				idx = i
+				break
+			}
+		}
+
+		selected = append(selected, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+
+	return selected, nil
+}
+
+func weight(c *domain.ReviewerCandidate) float64 {
+	return 1 / float64(1+c.OpenReviews)
+}
+
+// lockedRand is a goroutine-safe source of randomness shared by selectors.
+type lockedRand struct {
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+func newLockedRand() *lockedRand {
+	return &lockedRand{
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// shuffled returns a shuffled copy of candidates.
+func (r *lockedRand) shuffled(candidates []*domain.ReviewerCandidate) []*domain.ReviewerCandidate {
+	// Create a copy to avoid modifying the original slice
+	shuffled := make([]*domain.ReviewerCandidate, len(candidates))
+	copy(shuffled, candidates)
+
+	r.mu.Lock()
+	r.random.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	r.mu.Unlock()
+
+	return shuffled
+}
+
+func (r *lockedRand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.random.Float64()
+}
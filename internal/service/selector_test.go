@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	"reflect"
+	"testing"
+)
+
+// cursorRepository keeps round_robin cursors in memory.
+type cursorRepository struct {
+	repository.TeamRepository
+	cursors map[CandidatePool]string
+}
+
+func (r *cursorRepository) LockRoundRobinCursor(ctx context.Context, teamName, pool string) (string, error) {
+	return r.cursors[CandidatePool{TeamName: teamName, Name: pool}], nil
+}
+
+func (r *cursorRepository) SetRoundRobinCursor(ctx context.Context, teamName, pool, userID string) error {
+	r.cursors[CandidatePool{TeamName: teamName, Name: pool}] = userID
+	return nil
+}
+
+// passTransactor runs functions as they are; cursorRepository needs no transaction.
+type passTransactor struct {
+	repository.Transactor
+}
+
+func (passTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func candidates(userIDs ...string) []*domain.ReviewerCandidate {
+	result := make([]*domain.ReviewerCandidate, len(userIDs))
+	for i, userID := range userIDs {
+		result[i] = &domain.ReviewerCandidate{User: &domain.User{UserID: userID}}
+	}
+	return result
+}
+
+func userIDsOf(selected []*domain.ReviewerCandidate) []string {
+	ids := make([]string, len(selected))
+	for i, candidate := range selected {
+		ids[i] = candidate.UserID
+	}
+	return ids
+}
+
+func TestRoundRobinSelectorPools(t *testing.T) {
+	selector := &roundRobinSelector{
+		teamRepo: &cursorRepository{cursors: make(map[CandidatePool]string)},
+		tx:       passTransactor{},
+	}
+	members := CandidatePool{TeamName: "backend", Name: poolMembers}
+	owners := CandidatePool{TeamName: "backend", Name: poolOwners}
+
+	// Picks from another pool of the team don't move the members' position
+	steps := []struct {
+		pool       CandidatePool
+		candidates []*domain.ReviewerCandidate
+		want       []string
+	}{
+		{pool: members, candidates: candidates("u1", "u2", "u3"), want: []string{"u1"}},
+		{pool: owners, candidates: candidates("u3", "u9"), want: []string{"u3"}},
+		{pool: members, candidates: candidates("u1", "u2", "u3"), want: []string{"u2"}},
+		{pool: members, candidates: candidates("u3", "u1", "u2"), want: []string{"u3"}},
+		{pool: owners, candidates: candidates("u3", "u9"), want: []string{"u9"}},
+		{pool: members, candidates: candidates("u1", "u2", "u3"), want: []string{"u1"}},
+	}
+
+	for i, step := range steps {
+		selected, err := selector.Select(context.Background(), step.pool, step.candidates, 1)
+		if err != nil {
+			t.Fatalf("step %d: Select() error = %v", i, err)
+		}
+		if got := userIDsOf(selected); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d: Select(%s) = %v, want %v", i, step.pool.Name, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/repository"
+)
+
+type StatsService struct {
+	repo     repository.StatsRepository
+	teamRepo repository.TeamRepository
+	logger   *logger.Logger
+}
+
+func NewStatsService(repo repository.StatsRepository, teamRepo repository.TeamRepository, logger *logger.Logger) *StatsService {
+	return &StatsService{
+		repo:     repo,
+		teamRepo: teamRepo,
+		logger:   logger.Component("service/stats"),
+	}
+}
+
+// UserStats retrieves review statistics per user within the window.
+// Empty teamName means all users.
+func (s *StatsService) UserStats(ctx context.Context, teamName string, window domain.StatsWindow) ([]*domain.UserStats, error) {
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+
+	if teamName != "" {
+		exists, err := s.teamRepo.TeamExists(ctx, teamName)
+		if err != nil {
+			return nil, fmt.Errorf("check team exists: %w", err)
+		}
+		if !exists {
+			return nil, domain.ErrTeamNotFound
+		}
+	}
+
+	stats, err := s.repo.UserStats(ctx, teamName, window)
+	if err != nil {
+		return nil, fmt.Errorf("get user stats: %w", err)
+	}
+
+	s.logger.Info("user stats retrieved",
+		"team_name", teamName,
+		"count", len(stats),
+	)
+
+	return stats, nil
+}
+
+// TeamStats retrieves review statistics per team within the window.
+func (s *StatsService) TeamStats(ctx context.Context, window domain.StatsWindow) ([]*domain.TeamStats, error) {
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.TeamStats(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("get team stats: %w", err)
+	}
+
+	s.logger.Info("team stats retrieved", "count", len(stats))
+
+	return stats, nil
+}
+
+// CycleTime retrieves turnaround percentiles of pull requests merged within the window.
+func (s *StatsService) CycleTime(ctx context.Context, groupBy domain.CycleTimeGroup, window domain.StatsWindow) ([]*domain.CycleTimeStats, error) {
+	if !groupBy.IsValid() {
+		return nil, fmt.Errorf("%w: unknown group_by %q", domain.ErrInvalidRequest, groupBy)
+	}
+
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.CycleTime(ctx, groupBy, window)
+	if err != nil {
+		return nil, fmt.Errorf("get cycle time: %w", err)
+	}
+
+	s.logger.Info("cycle time retrieved",
+		"group_by", groupBy,
+		"count", len(stats),
+	)
+
+	return stats, nil
+}
+
+// validateWindow checks that the window bounds are ordered.
+func validateWindow(window domain.StatsWindow) error {
+	if window.From != nil && window.To != nil && !window.From.Before(*window.To) {
+		return fmt.Errorf("%w: from must be before to", domain.ErrInvalidRequest)
+	}
+	return nil
+}
@@ -10,15 +10,36 @@ import (
 	. "github.com/go-ozzo/ozzo-validation"
 )
 
+// maxReviewersLimit is the upper bound for team's max_reviewers setting.
+const maxReviewersLimit = 10
+
+// maxReviewSLAHours is the upper bound for team's review_sla_hours setting.
+const maxReviewSLAHours = 24 * 30
+
 type TeamService struct {
-	repo   repository.TeamRepository
-	logger *logger.Logger
+	repo      repository.TeamRepository
+	userRepo  repository.UserRepository
+	tx        repository.Transactor
+	prService *PRService
+	notifier  EventNotifier
+	logger    *logger.Logger
 }
 
-func NewTeamService(teamRepo repository.TeamRepository, logger *logger.Logger) *TeamService {
+func NewTeamService(
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	prService *PRService,
+	notifier EventNotifier,
+	logger *logger.Logger,
+) *TeamService {
 	return &TeamService{
-		repo:   teamRepo,
-		logger: logger,
+		repo:      teamRepo,
+		userRepo:  userRepo,
+		tx:        tx,
+		prService: prService,
+		notifier:  notifier,
+		logger:    logger,
 	}
 }
 
@@ -30,7 +51,7 @@ type CreateTeamResponse struct {
 // Validates team structure before persistence.
 func (s *TeamService) CreateTeam(ctx context.Context, team *domain.Team) (*CreateTeamResponse, error) {
 	if err := s.validateTeam(team); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
 	}
 
 	exists, err := s.repo.TeamExists(ctx, team.TeamName)
@@ -42,6 +63,10 @@ func (s *TeamService) CreateTeam(ctx context.Context, team *domain.Team) (*Creat
 		return nil, domain.ErrTeamExists
 	}
 
+	if err := s.validateFallbacks(ctx, team.TeamName, team.FallbackTeams); err != nil {
+		return nil, err
+	}
+
 	created, err := s.repo.CreateTeamWithMembers(ctx, team)
 	if err != nil {
 		return nil, fmt.Errorf("create team: %w", err)
@@ -70,12 +95,179 @@ func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Tea
 	return team, nil
 }
 
+// UpdateSettings applies a partial update to team's reviewer assignment settings
+// and returns the updated team.
+func (s *TeamService) UpdateSettings(ctx context.Context, teamName string, update domain.TeamSettingsUpdate) (*domain.Team, error) {
+	settings, err := s.repo.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("get team settings: %w", err)
+	}
+
+	update.Apply(settings)
+
+	if err := s.validateSettings(settings); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	if err := s.validateFallbacks(ctx, teamName, settings.FallbackTeams); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateSettings(ctx, teamName, settings); err != nil {
+		return nil, fmt.Errorf("update team settings: %w", err)
+	}
+
+	s.logger.Info("team settings updated",
+		"team_name", teamName,
+		"reviewer_strategy", settings.ReviewerStrategy,
+		"min_reviewers", settings.MinReviewers,
+		"max_reviewers", settings.MaxReviewers,
+		"fallback_teams", settings.FallbackTeams,
+		"required_approvals", settings.RequiredApprovals,
+		"auto_reassign_on_absence", settings.AutoReassignOnAbsence,
+		"review_sla_hours", settings.ReviewSLAHours,
+		"escalate_overdue", settings.EscalateOverdue,
+	)
+
+	return s.GetTeam(ctx, teamName)
+}
+
+// AddMember adds a new or teamless user to a team and returns the updated team.
+// Users of other teams must be moved explicitly.
+func (s *TeamService) AddMember(ctx context.Context, teamName string, member domain.TeamMember) (*domain.Team, error) {
+	if err := s.validateMember(member); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("check team exists: %w", err)
+	}
+
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	if err := s.repo.AddMember(ctx, teamName, member); err != nil {
+		return nil, fmt.Errorf("add member: %w", err)
+	}
+
+	s.logger.Info("team member added",
+		"team_name", teamName,
+		"user_id", member.UserID,
+	)
+
+	return s.GetTeam(ctx, teamName)
+}
+
+type RemoveMemberResponse struct {
+	Team          *domain.Team          `json:"team"`
+	Reassignments []domain.Reassignment `json:"reassignments"`
+	Unreassigned  []domain.Reassignment `json:"unreassigned"` // ревьюер снят, замены не нашлось
+}
+
+// RemoveMember detaches a user from a team and deactivates them.
+// User's open reviews are reassigned in the same transaction.
+func (s *TeamService) RemoveMember(ctx context.Context, teamName, userID string) (*RemoveMemberResponse, error) {
+	response := &RemoveMemberResponse{}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Reassign first: replacements come from the team the user is leaving
+		reassignments, err := s.prService.ReassignOpenReviews(ctx, userID, domain.SystemActor, domain.ReasonRemoved)
+		if err != nil {
+			return fmt.Errorf("reassign open reviews: %w", err)
+		}
+		response.Reassignments, response.Unreassigned = domain.SplitReassignments(reassignments)
+
+		if err := s.repo.RemoveMember(ctx, teamName, userID); err != nil {
+			return fmt.Errorf("remove member: %w", err)
+		}
+
+		return s.notifyDeactivated(ctx, userID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("team member removed",
+		"team_name", teamName,
+		"user_id", userID,
+		"reassigned", len(response.Reassignments),
+		"unreassigned", len(response.Unreassigned),
+	)
+
+	response.Team, err = s.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// DeleteTeam deletes a team. Refuses while team members author or review
+// draft or open pull requests. Former members stay as deactivated teamless users.
+func (s *TeamService) DeleteTeam(ctx context.Context, teamName string) error {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		hasOpen, err := s.repo.HasOpenPRs(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if hasOpen {
+			return fmt.Errorf("%w: %s", domain.ErrTeamHasOpenPRs, teamName)
+		}
+
+		team, err := s.repo.GetTeamWithMembers(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.DeleteTeam(ctx, teamName); err != nil {
+			return err
+		}
+
+		for _, member := range team.Members {
+			if !member.IsActive {
+				continue
+			}
+			if err := s.notifyDeactivated(ctx, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("delete team: %w", err)
+	}
+
+	s.logger.Info("team deleted", "team_name", teamName)
+
+	return nil
+}
+
+// notifyDeactivated emits user.deactivated for a user deactivated on leaving their team.
+func (s *TeamService) notifyDeactivated(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	return s.notifier.Notify(ctx, domain.NewEvent(domain.EventUserDeactivated, domain.UserEventData{User: user}))
+}
+
 // validateTeam validates team structure and member data.
 func (s *TeamService) validateTeam(team *domain.Team) error {
 	if team == nil {
 		return errors.New("team is nil")
 	}
 
+	if err := s.validateSettings(&team.TeamSettings); err != nil {
+		return err
+	}
+
 	return ValidateStruct(team,
 		Field(&team.TeamName,
 			Required,
@@ -89,6 +281,66 @@ func (s *TeamService) validateTeam(team *domain.Team) error {
 	)
 }
 
+// validateSettings validates reviewer assignment settings.
+func (s *TeamService) validateSettings(settings *domain.TeamSettings) error {
+	return ValidateStruct(settings,
+		Field(&settings.ReviewerStrategy,
+			Required,
+			By(validateStrategy),
+		),
+		Field(&settings.MinReviewers,
+			Min(0),
+			Max(settings.MaxReviewers),
+		),
+		Field(&settings.MaxReviewers,
+			Required,
+			Min(1),
+			Max(maxReviewersLimit),
+		),
+		Field(&settings.RequiredApprovals,
+			Min(0),
+			Max(settings.MaxReviewers),
+		),
+		Field(&settings.ReviewSLAHours,
+			Min(0),
+			Max(maxReviewSLAHours),
+		),
+	)
+}
+
+// validateFallbacks checks that fallback teams exist, are unique and don't reference the team itself.
+func (s *TeamService) validateFallbacks(ctx context.Context, teamName string, fallbacks []string) error {
+	seen := make(map[string]bool, len(fallbacks))
+	for _, fallback := range fallbacks {
+		if fallback == teamName {
+			return fmt.Errorf("%w: team cannot be its own fallback", domain.ErrInvalidRequest)
+		}
+		if seen[fallback] {
+			return fmt.Errorf("%w: duplicate fallback team %s", domain.ErrInvalidRequest, fallback)
+		}
+		seen[fallback] = true
+
+		exists, err := s.repo.TeamExists(ctx, fallback)
+		if err != nil {
+			return fmt.Errorf("check fallback team exists: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("fallback team %s: %w", fallback, domain.ErrTeamNotFound)
+		}
+	}
+
+	return nil
+}
+
+// validateStrategy checks that reviewer strategy is a known one.
+func validateStrategy(value interface{}) error {
+	strategy, ok := value.(domain.ReviewerStrategy)
+	if !ok || !strategy.IsValid() {
+		return errors.New("unknown reviewer strategy")
+	}
+	return nil
+}
+
 // validateMember validates individual team member data.
 func (s *TeamService) validateMember(value interface{}) error {
 	member, ok := value.(domain.TeamMember)
@@ -105,5 +357,8 @@ func (s *TeamService) validateMember(value interface{}) error {
 			Required,
 			Length(1, 255),
 		),
+		Field(&member.MaxOpenReviews,
+			Min(0),
+		),
 	)
 }
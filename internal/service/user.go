@@ -2,41 +2,347 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/ZertGraf/avito-test/internal/domain"
 	"github.com/ZertGraf/avito-test/internal/pkg/logger"
 	"github.com/ZertGraf/avito-test/internal/repository"
+	"time"
 )
 
 type UserService struct {
-	repo   repository.UserRepository
-	logger *logger.Logger
+	repo      repository.UserRepository
+	teamRepo  repository.TeamRepository
+	tx        repository.Transactor
+	prService *PRService
+	notifier  EventNotifier
+	logger    *logger.Logger
 }
 
-func NewUserService(repo repository.UserRepository, logger *logger.Logger) *UserService {
+func NewUserService(
+	repo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	tx repository.Transactor,
+	prService *PRService,
+	notifier EventNotifier,
+	logger *logger.Logger,
+) *UserService {
 	return &UserService{
-		repo:   repo,
-		logger: logger,
+		repo:      repo,
+		teamRepo:  teamRepo,
+		tx:        tx,
+		prService: prService,
+		notifier:  notifier,
+		logger:    logger,
 	}
 }
 
+// GetUser retrieves a user with their open review and authored PR counts.
+func (s *UserService) GetUser(ctx context.Context, userID string) (*domain.UserProfile, error) {
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	s.logger.Info("user retrieved",
+		"user_id", userID,
+		"team", profile.TeamName,
+	)
+
+	return profile, nil
+}
+
 type SetIsActiveResponse struct {
-	User *domain.User `json:"user"`
+	User          *domain.User          `json:"user"`
+	Reassignments []domain.Reassignment `json:"reassignments,omitempty"`
+	Unreassigned  []domain.Reassignment `json:"unreassigned,omitempty"` // ревьюер снят, замены не нашлось
 }
 
 // SetIsActive updates user's activity status.
 // Used to enable/disable users from reviewer assignment pool.
-func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
-	user, err := s.repo.SetIsActive(ctx, userID, isActive)
+// On deactivation with reassign set, user's open reviews are handed over
+// to other active reviewers in the same transaction. Setting the status
+// the user already has changes nothing and emits no events.
+func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive, reassign bool) (*SetIsActiveResponse, error) {
+	response := &SetIsActiveResponse{}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, changed, err := s.repo.SetIsActive(ctx, userID, isActive)
+		if err != nil {
+			return fmt.Errorf("set is_active: %w", err)
+		}
+		response.User = user
+
+		if isActive || !changed {
+			return nil
+		}
+
+		if err := s.notifier.Notify(ctx, domain.NewEvent(domain.EventUserDeactivated, domain.UserEventData{User: user})); err != nil {
+			return err
+		}
+
+		if !reassign {
+			return nil
+		}
+
+		reassignments, err := s.prService.ReassignOpenReviews(ctx, userID, domain.SystemActor, domain.ReasonDeactivated)
+		if err != nil {
+			return fmt.Errorf("reassign open reviews: %w", err)
+		}
+		response.Reassignments, response.Unreassigned = domain.SplitReassignments(reassignments)
+
+		return nil
+	})
+
 	if err != nil {
-		return nil, fmt.Errorf("set is_active: %w", err)
+		return nil, err
 	}
 
 	s.logger.Info("user activity status changed",
 		"user_id", userID,
 		"is_active", isActive,
+		"team", response.User.TeamName,
+		"reassigned", len(response.Reassignments),
+		"unreassigned", len(response.Unreassigned),
+	)
+
+	return response, nil
+}
+
+// SetMaxOpenReviews updates user's cap on simultaneously open reviews.
+// Users at their cap are skipped during reviewer assignment. A nil cap removes the limit.
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error) {
+	if maxOpenReviews != nil && *maxOpenReviews < 0 {
+		return nil, fmt.Errorf("%w: max_open_reviews must be non-negative", domain.ErrInvalidRequest)
+	}
+
+	user, err := s.repo.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
+	if err != nil {
+		return nil, fmt.Errorf("set max_open_reviews: %w", err)
+	}
+
+	s.logger.Info("user review cap changed",
+		"user_id", userID,
+		"max_open_reviews", maxOpenReviews,
 		"team", user.TeamName,
 	)
 
 	return user, nil
 }
+
+// SetWorkingHours updates user's timezone and working hours.
+// Reviewers within their working hours are preferred during assignment.
+// Empty workStart and workEnd remove the restriction; empty timezone means UTC.
+func (s *UserService) SetWorkingHours(ctx context.Context, userID, timezone, workStart, workEnd string) (*domain.User, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	if err := validateWorkingHours(timezone, workStart, workEnd); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	user, err := s.repo.SetWorkingHours(ctx, userID, timezone, workStart, workEnd)
+	if err != nil {
+		return nil, fmt.Errorf("set working hours: %w", err)
+	}
+
+	s.logger.Info("user working hours changed",
+		"user_id", userID,
+		"timezone", timezone,
+		"work_start", workStart,
+		"work_end", workEnd,
+	)
+
+	return user, nil
+}
+
+// validateWorkingHours checks that timezone is known and working hours are
+// either both empty or both valid HH:MM times.
+func validateWorkingHours(timezone, workStart, workEnd string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", timezone)
+	}
+
+	if workStart == "" && workEnd == "" {
+		return nil
+	}
+
+	start, err := domain.ParseClock(workStart)
+	if err != nil {
+		return fmt.Errorf("work_start: %w", err)
+	}
+
+	end, err := domain.ParseClock(workEnd)
+	if err != nil {
+		return fmt.Errorf("work_end: %w", err)
+	}
+
+	if start == end {
+		return errors.New("work_start and work_end must differ")
+	}
+
+	return nil
+}
+
+type MoveTeamResponse struct {
+	User          *domain.User          `json:"user"`
+	Reassignments []domain.Reassignment `json:"reassignments,omitempty"`
+	Unreassigned  []domain.Reassignment `json:"unreassigned,omitempty"` // ревьюер снят, замены не нашлось
+}
+
+// MoveTeam moves a user to another team.
+// With reassign set, user's open reviews are handed over to members of
+// the team they leave; otherwise the user keeps reviewing them.
+// Moving a user to the team they are in is rejected.
+func (s *UserService) MoveTeam(ctx context.Context, userID, teamName string, reassign bool) (*MoveTeamResponse, error) {
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("check team exists: %w", err)
+	}
+
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	response := &MoveTeamResponse{}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := s.repo.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+
+		if user.TeamName == teamName {
+			return fmt.Errorf("%w: %s is already in team %s", domain.ErrInvalidRequest, userID, teamName)
+		}
+
+		// Reassign before moving so replacements come from the previous team
+		if reassign {
+			reassignments, err := s.prService.ReassignOpenReviews(ctx, userID, domain.SystemActor, domain.ReasonMovedTeam)
+			if err != nil {
+				return fmt.Errorf("reassign open reviews: %w", err)
+			}
+			response.Reassignments, response.Unreassigned = domain.SplitReassignments(reassignments)
+		}
+
+		if err := s.teamRepo.MoveMember(ctx, userID, teamName); err != nil {
+			return fmt.Errorf("move member: %w", err)
+		}
+
+		response.User, err = s.repo.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user moved to another team",
+		"user_id", userID,
+		"team", teamName,
+		"reassigned", len(response.Reassignments),
+		"unreassigned", len(response.Unreassigned),
+	)
+
+	return response, nil
+}
+
+// maxBulkUsers is the upper bound for the number of users deactivated at once.
+const maxBulkUsers = 1000
+
+type BulkDeactivateResponse struct {
+	Users         []*domain.User        `json:"users"`
+	Reassignments []domain.Reassignment `json:"reassignments"`
+	Unreassigned  []domain.Reassignment `json:"unreassigned"` // ревьюер снят, замены не нашлось; reason - почему
+}
+
+// BulkDeactivate deactivates members of a team or the listed users in one transaction
+// and reassigns their open reviews to remaining active users, falling back to fallback
+// teams of the author's team. Reviews without a replacement lose the reviewer and are
+// reported as unreassigned with the reason. Exactly one of teamName and userIDs must be set.
+func (s *UserService) BulkDeactivate(ctx context.Context, teamName string, userIDs []string) (*BulkDeactivateResponse, error) {
+	if (teamName == "") == (len(userIDs) == 0) {
+		return nil, fmt.Errorf("%w: either team_name or user_ids is required", domain.ErrInvalidRequest)
+	}
+
+	if len(userIDs) > maxBulkUsers {
+		return nil, fmt.Errorf("%w: at most %d user_ids", domain.ErrInvalidRequest, maxBulkUsers)
+	}
+
+	response := &BulkDeactivateResponse{
+		Users:         []*domain.User{},
+		Reassignments: []domain.Reassignment{},
+		Unreassigned:  []domain.Reassignment{},
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Members are read within the same transaction as their deactivation
+		if teamName != "" {
+			team, err := s.teamRepo.GetTeamWithMembers(ctx, teamName)
+			if err != nil {
+				return fmt.Errorf("get team: %w", err)
+			}
+			for _, member := range team.Members {
+				userIDs = append(userIDs, member.UserID)
+			}
+		}
+
+		seen := make(map[string]bool, len(userIDs))
+
+		// Deactivate everyone first so nobody of them is picked as a replacement
+		deactivated := make([]*domain.User, 0, len(userIDs))
+		for _, userID := range userIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+
+			user, changed, err := s.repo.SetIsActive(ctx, userID, false)
+			if err != nil {
+				return fmt.Errorf("deactivate %s: %w", userID, err)
+			}
+			response.Users = append(response.Users, user)
+
+			// Users who were already inactive keep their reviews as they are
+			if !changed {
+				continue
+			}
+			deactivated = append(deactivated, user)
+
+			if err := s.notifier.Notify(ctx, domain.NewEvent(domain.EventUserDeactivated, domain.UserEventData{User: user})); err != nil {
+				return err
+			}
+		}
+
+		for _, user := range deactivated {
+			reassignments, err := s.prService.ReassignOpenReviews(ctx, user.UserID, domain.SystemActor, domain.ReasonDeactivated)
+			if err != nil {
+				return fmt.Errorf("reassign open reviews of %s: %w", user.UserID, err)
+			}
+
+			reassigned, unreassigned := domain.SplitReassignments(reassignments)
+			response.Reassignments = append(response.Reassignments, reassigned...)
+			response.Unreassigned = append(response.Unreassigned, unreassigned...)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("users deactivated in bulk",
+		"team", teamName,
+		"users", len(response.Users),
+		"reassigned", len(response.Reassignments),
+		"unreassigned", len(response.Unreassigned),
+	)
+
+	return response, nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZertGraf/avito-test/internal/domain"
+	"github.com/ZertGraf/avito-test/internal/pkg/logger"
+	"github.com/ZertGraf/avito-test/internal/pkg/webhook"
+	"github.com/ZertGraf/avito-test/internal/repository"
+	. "github.com/go-ozzo/ozzo-validation"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxDeliveriesLimit is the upper bound for the number of listed deliveries.
+const maxDeliveriesLimit = 200
+
+type WebhookConfig struct {
+	BatchSize   int           // доставок за один проход воркера
+	MaxAttempts int           // после стольких неудачных попыток доставка FAILED
+	RetryBase   time.Duration // задержка перед второй попыткой, дальше удваивается
+	RetryMax    time.Duration // верхняя граница задержки
+	Lease       time.Duration // через сколько повторить доставку, если отправитель упал
+}
+
+type WebhookService struct {
+	repo   repository.WebhookRepository
+	sender *webhook.Sender
+	config WebhookConfig
+	logger *logger.Logger
+}
+
+func NewWebhookService(
+	repo repository.WebhookRepository,
+	sender *webhook.Sender,
+	config WebhookConfig,
+	logger *logger.Logger,
+) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		config: config,
+		logger: logger.Component("service/webhook"),
+	}
+}
+
+// AddSubscription validates and stores a subscription.
+func (s *WebhookService) AddSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	if err := validateSubscription(sub); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRequest, err)
+	}
+
+	created, err := s.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		return nil, fmt.Errorf("create subscription: %w", err)
+	}
+
+	s.logger.Info("webhook subscription added",
+		"subscription_id", created.SubscriptionID,
+		"url", created.URL,
+		"event_types", created.EventTypes,
+	)
+
+	return created, nil
+}
+
+// ListSubscriptions retrieves all subscriptions.
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+// RemoveSubscription deletes a subscription and its pending deliveries.
+func (s *WebhookService) RemoveSubscription(ctx context.Context, subscriptionID int64) error {
+	if err := s.repo.DeleteSubscription(ctx, subscriptionID); err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+
+	s.logger.Info("webhook subscription removed", "subscription_id", subscriptionID)
+
+	return nil
+}
+
+// ListDeliveries retrieves the latest deliveries of a subscription.
+// Empty status means any status; zero limit means default page size.
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, status domain.DeliveryStatus, limit int) ([]*domain.WebhookDelivery, error) {
+	switch status {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidRequest, status)
+	}
+
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit < 0 || limit > maxDeliveriesLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidRequest, maxDeliveriesLimit)
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, subscriptionID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// HandleEvent queues a delivery of the outbox event to every subscription to its type.
+// Subscribed to the in-process publisher, it runs within the relay transaction.
+func (s *WebhookService) HandleEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	queued, err := s.repo.Enqueue(ctx, event.Type, event.Payload)
+	if err != nil {
+		return fmt.Errorf("queue webhook deliveries: %w", err)
+	}
+
+	if queued > 0 {
+		s.logger.Debug("webhook deliveries queued", "event_type", event.Type, "count", queued)
+	}
+
+	return nil
+}
+
+// DeliverDue sends a batch of due deliveries. Failed attempts are retried with
+// exponential backoff until MaxAttempts. Returns the number of delivered ones.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// Unsent deliveries are retried when their lease expires
+			return delivered, ctx.Err()
+		}
+
+		status, sendErr := s.sender.Send(ctx, webhook.Request{
+			URL:        delivery.URL,
+			Secret:     delivery.Secret,
+			Event:      string(delivery.EventType),
+			DeliveryID: strconv.FormatInt(delivery.DeliveryID, 10),
+			Body:       delivery.Payload,
+		})
+
+		if sendErr == nil {
+			if err := s.repo.MarkDelivered(ctx, delivery.DeliveryID, status); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		var responseStatus *int
+		if status != 0 {
+			responseStatus = &status
+		}
+
+		var nextAttemptAt *time.Time
+		if delivery.Attempts < s.config.MaxAttempts {
+			next := time.Now().Add(retryDelay(s.config.RetryBase, s.config.RetryMax, delivery.Attempts))
+			nextAttemptAt = &next
+		}
+
+		s.logger.Warn("webhook delivery failed",
+			"delivery_id", delivery.DeliveryID,
+			"subscription_id", delivery.SubscriptionID,
+			"attempt", delivery.Attempts,
+			"status", status,
+			"error", sendErr,
+			"next_attempt_at", nextAttemptAt,
+		)
+
+		if err := s.repo.MarkFailed(ctx, delivery.DeliveryID, responseStatus, sendErr.Error(), nextAttemptAt); err != nil {
+			return delivered, err
+		}
+	}
+
+	if len(deliveries) > 0 {
+		s.logger.Info("webhook deliveries processed",
+			"claimed", len(deliveries),
+			"delivered", delivered,
+		)
+	}
+
+	return delivered, nil
+}
+
+// validateSubscription checks the endpoint URL, the secret and event types.
+func validateSubscription(sub *domain.WebhookSubscription) error {
+	if sub == nil {
+		return errors.New("subscription is nil")
+	}
+
+	return ValidateStruct(sub,
+		Field(&sub.URL,
+			Required,
+			Length(1, 2048),
+			By(validateWebhookURL),
+		),
+		Field(&sub.Secret,
+			Required,
+			Length(16, 255),
+		),
+		Field(&sub.EventTypes,
+			Required,
+			By(validateEventTypes),
+		),
+	)
+}
+
+// validateWebhookURL checks that the value is an absolute http(s) URL.
+func validateWebhookURL(value interface{}) error {
+	raw, _ := value.(string)
+	parsed, err := url.ParseRequestURI(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// validateEventTypes checks that event types are known and unique.
+func validateEventTypes(value interface{}) error {
+	eventTypes, _ := value.([]domain.EventType)
+	seen := make(map[domain.EventType]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !eventType.IsValid() {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+		if seen[eventType] {
+			return fmt.Errorf("duplicate event type %q", eventType)
+		}
+		seen[eventType] = true
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+-- 002_team_reviewer_strategy.sql
+
+ALTER TABLE teams
+    ADD COLUMN reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random'
+        CHECK (reviewer_strategy IN ('random', 'round_robin', 'least_loaded', 'weighted_random'));
+
+-- Last reviewer picked by the round_robin strategy in every candidate pool
+-- of a team, shared by all instances
+CREATE TABLE round_robin_cursors (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    pool VARCHAR(64) NOT NULL,
+    last_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (team_name, pool)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS round_robin_cursors;
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
@@ -0,0 +1,9 @@
+-- 003_user_review_cap.sql
+
+-- NULL means the user has no limit on open reviews
+ALTER TABLE users
+    ADD COLUMN max_open_reviews INTEGER CHECK (max_open_reviews >= 0);
+
+---- create above / drop below ----
+
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
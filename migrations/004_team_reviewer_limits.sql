@@ -0,0 +1,13 @@
+-- 004_team_reviewer_limits.sql
+
+ALTER TABLE teams
+    ADD COLUMN min_reviewers INTEGER NOT NULL DEFAULT 0 CHECK (min_reviewers >= 0),
+    ADD COLUMN max_reviewers INTEGER NOT NULL DEFAULT 2 CHECK (max_reviewers >= 1),
+    ADD CONSTRAINT teams_reviewers_range_check CHECK (min_reviewers <= max_reviewers);
+
+---- create above / drop below ----
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_reviewers_range_check,
+    DROP COLUMN IF EXISTS max_reviewers,
+    DROP COLUMN IF EXISTS min_reviewers;
@@ -0,0 +1,22 @@
+-- 005_team_fallbacks.sql
+
+CREATE TABLE team_fallbacks (
+                                team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+                                fallback_team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+                                position INTEGER NOT NULL,
+                                PRIMARY KEY (team_name, fallback_team_name),
+                                CHECK (team_name <> fallback_team_name)
+);
+
+-- team the reviewer was picked from (author's team or one of its fallbacks)
+ALTER TABLE pr_reviewers ADD COLUMN source_team VARCHAR(255);
+
+UPDATE pr_reviewers r
+SET source_team = u.team_name
+FROM users u
+WHERE u.user_id = r.reviewer_id;
+
+---- create above / drop below ----
+
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS source_team;
+DROP TABLE IF EXISTS team_fallbacks;
@@ -0,0 +1,21 @@
+-- 006_code_owners.sql
+
+CREATE TABLE code_owner_rules (
+                                  rule_id BIGSERIAL PRIMARY KEY,
+                                  team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+                                  pattern VARCHAR(1024) NOT NULL,
+                                  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_code_owner_rules_team ON code_owner_rules(team_name, rule_id);
+
+CREATE TABLE code_owner_rule_owners (
+                                        rule_id BIGINT NOT NULL REFERENCES code_owner_rules(rule_id) ON DELETE CASCADE,
+                                        user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+                                        PRIMARY KEY (rule_id, user_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS code_owner_rule_owners;
+DROP TABLE IF EXISTS code_owner_rules;
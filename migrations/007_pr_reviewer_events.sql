@@ -0,0 +1,33 @@
+-- 007_pr_reviewer_events.sql
+
+-- append-only history of reviewer assignments
+CREATE TABLE pr_reviewer_events (
+                                    event_id BIGSERIAL PRIMARY KEY,
+                                    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
+                                    event_type VARCHAR(20) NOT NULL
+                                        CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'MERGED_WITH')),
+                                    reviewer_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+                                    previous_reviewer_id VARCHAR(255) REFERENCES users(user_id),
+                                    actor_id VARCHAR(255) NOT NULL,
+                                    reason TEXT NOT NULL DEFAULT '',
+                                    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pr_reviewer_events_pr ON pr_reviewer_events(pull_request_id, event_id);
+
+-- seed history with assignments that existed before the table
+INSERT INTO pr_reviewer_events (pull_request_id, event_type, reviewer_id, actor_id, reason, created_at)
+SELECT r.pull_request_id, 'ASSIGNED', r.reviewer_id, 'system', 'migrated', r.assigned_at
+FROM pr_reviewers r
+ORDER BY r.assigned_at;
+
+INSERT INTO pr_reviewer_events (pull_request_id, event_type, reviewer_id, actor_id, reason, created_at)
+SELECT r.pull_request_id, 'MERGED_WITH', r.reviewer_id, 'system', 'migrated', pr.merged_at
+FROM pr_reviewers r
+INNER JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+WHERE pr.status = 'MERGED'
+ORDER BY pr.merged_at;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS pr_reviewer_events;
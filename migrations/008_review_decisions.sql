@@ -0,0 +1,26 @@
+-- 008_review_decisions.sql
+
+ALTER TABLE pr_reviewers
+    ADD COLUMN decision VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (decision IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED')),
+    ADD COLUMN decided_at TIMESTAMPTZ;
+
+-- 0 means merge doesn't require approvals
+ALTER TABLE teams
+    ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+ALTER TABLE pr_reviewer_events DROP CONSTRAINT pr_reviewer_events_event_type_check;
+ALTER TABLE pr_reviewer_events ADD CONSTRAINT pr_reviewer_events_event_type_check
+    CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'MERGED_WITH', 'REVIEWED'));
+
+---- create above / drop below ----
+
+DELETE FROM pr_reviewer_events WHERE event_type = 'REVIEWED';
+ALTER TABLE pr_reviewer_events DROP CONSTRAINT pr_reviewer_events_event_type_check;
+ALTER TABLE pr_reviewer_events ADD CONSTRAINT pr_reviewer_events_event_type_check
+    CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'MERGED_WITH'));
+
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS decided_at,
+    DROP COLUMN IF EXISTS decision;
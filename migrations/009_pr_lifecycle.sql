@@ -0,0 +1,25 @@
+-- 009_pr_lifecycle.sql
+
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check
+    CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
+
+ALTER TABLE pull_requests ADD COLUMN closed_at TIMESTAMPTZ;
+
+ALTER TABLE pr_reviewer_events DROP CONSTRAINT pr_reviewer_events_event_type_check;
+ALTER TABLE pr_reviewer_events ADD CONSTRAINT pr_reviewer_events_event_type_check
+    CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'MERGED_WITH', 'REVIEWED', 'CLOSED_WITH'));
+
+---- create above / drop below ----
+
+DELETE FROM pr_reviewer_events WHERE event_type = 'CLOSED_WITH';
+ALTER TABLE pr_reviewer_events DROP CONSTRAINT pr_reviewer_events_event_type_check;
+ALTER TABLE pr_reviewer_events ADD CONSTRAINT pr_reviewer_events_event_type_check
+    CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED', 'MERGED_WITH', 'REVIEWED'));
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
+
+UPDATE pull_requests SET status = 'OPEN' WHERE status IN ('DRAFT', 'CLOSED');
+ALTER TABLE pull_requests DROP CONSTRAINT pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check
+    CHECK (status IN ('OPEN', 'MERGED'));
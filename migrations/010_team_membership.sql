@@ -0,0 +1,9 @@
+-- 010_team_membership.sql
+
+-- users removed from a team (or whose team was deleted) have no team
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+---- create above / drop below ----
+
+-- fails while teamless users exist; move them into a team first
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
@@ -0,0 +1,28 @@
+-- 011_user_unavailability.sql
+
+CREATE TABLE user_unavailability (
+                                     unavailability_id BIGSERIAL PRIMARY KEY,
+                                     user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                                     starts_at TIMESTAMPTZ NOT NULL,
+                                     ends_at TIMESTAMPTZ NOT NULL,
+                                     reason TEXT NOT NULL DEFAULT '',
+    -- set once open reviews were reassigned after the absence started
+                                     processed_at TIMESTAMPTZ,
+    -- failed automatic reassignments are retried with backoff instead of blocking the queue
+                                     reassign_attempts INTEGER NOT NULL DEFAULT 0,
+                                     reassign_error TEXT NOT NULL DEFAULT '',
+                                     next_attempt_at TIMESTAMPTZ,
+                                     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                     CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_user_unavailability_user ON user_unavailability(user_id, ends_at);
+CREATE INDEX idx_user_unavailability_pending ON user_unavailability(starts_at) WHERE processed_at IS NULL;
+
+-- reassign open reviews of absent members automatically
+ALTER TABLE teams ADD COLUMN auto_reassign_on_absence BOOLEAN NOT NULL DEFAULT false;
+
+---- create above / drop below ----
+
+ALTER TABLE teams DROP COLUMN IF EXISTS auto_reassign_on_absence;
+DROP TABLE IF EXISTS user_unavailability;
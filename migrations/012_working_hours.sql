@@ -0,0 +1,37 @@
+-- 012_working_hours.sql
+
+-- no working hours means the user may be assigned at any time
+ALTER TABLE users
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN work_start TIME,
+    ADD COLUMN work_end TIME,
+    ADD CONSTRAINT users_working_hours_check
+        CHECK ((work_start IS NULL) = (work_end IS NULL));
+
+-- why the reviewer was picked, shown in PR responses
+ALTER TABLE pr_reviewers
+    ADD COLUMN reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN explanation TEXT NOT NULL DEFAULT '';
+
+UPDATE pr_reviewers r
+SET reason = e.reason
+FROM (
+    SELECT DISTINCT ON (pull_request_id, reviewer_id) pull_request_id, reviewer_id, reason
+    FROM pr_reviewer_events
+    WHERE event_type IN ('ASSIGNED', 'REASSIGNED')
+    ORDER BY pull_request_id, reviewer_id, created_at DESC, event_id DESC
+) e
+WHERE e.pull_request_id = r.pull_request_id
+  AND e.reviewer_id = r.reviewer_id;
+
+---- create above / drop below ----
+
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS explanation,
+    DROP COLUMN IF EXISTS reason;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_working_hours_check,
+    DROP COLUMN IF EXISTS work_end,
+    DROP COLUMN IF EXISTS work_start,
+    DROP COLUMN IF EXISTS timezone;
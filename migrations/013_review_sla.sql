@@ -0,0 +1,16 @@
+-- 013_review_sla.sql
+
+-- 0 means the team has no review SLA
+ALTER TABLE teams
+    ADD COLUMN review_sla_hours INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_hours >= 0),
+    ADD COLUMN escalate_overdue BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_pr_reviewers_pending ON pr_reviewers(assigned_at) WHERE decision = 'PENDING';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_pr_reviewers_pending;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS escalate_overdue,
+    DROP COLUMN IF EXISTS review_sla_hours;
@@ -0,0 +1,18 @@
+-- 014_pr_listing.sql
+
+-- Keyset pagination of /pullRequest/list: newest first, ties broken by ID
+CREATE INDEX idx_pull_requests_created ON pull_requests(created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_status_created ON pull_requests(status, created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_author_created ON pull_requests(author_id, created_at DESC, pull_request_id DESC);
+CREATE INDEX idx_pull_requests_merged ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+
+-- Case-insensitive prefix search by name
+CREATE INDEX idx_pull_requests_name_prefix ON pull_requests(lower(pull_request_name) text_pattern_ops);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_pull_requests_name_prefix;
+DROP INDEX IF EXISTS idx_pull_requests_merged;
+DROP INDEX IF EXISTS idx_pull_requests_author_created;
+DROP INDEX IF EXISTS idx_pull_requests_status_created;
+DROP INDEX IF EXISTS idx_pull_requests_created;
@@ -0,0 +1,19 @@
+-- 016_outbox.sql
+
+-- Domain events written in the transaction of the change that caused them
+CREATE TABLE outbox (
+    event_id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ,
+    -- Transaction that wrote the event: the relay skips events of transactions that
+    -- may still be running, so a late commit can't slip behind already published events
+    xact_id xid8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX idx_outbox_unpublished ON outbox(event_id) WHERE published_at IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS outbox;
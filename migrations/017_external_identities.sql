@@ -0,0 +1,16 @@
+-- 017_external_identities.sql
+
+-- Logins on git hostings mapped to our users
+CREATE TABLE external_identities (
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('github', 'gitlab')),
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, login)
+);
+
+CREATE INDEX idx_external_identities_user ON external_identities(user_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS external_identities;
@@ -0,0 +1,36 @@
+-- 018_reviewer_sync.sql
+
+-- Reviewer changes waiting to be copied to the git hosting
+CREATE TABLE reviewer_sync_tasks (
+    task_id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    add_reviewer_id VARCHAR(255),
+    remove_reviewer_id VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (add_reviewer_id IS NOT NULL OR remove_reviewer_id IS NOT NULL)
+);
+
+CREATE INDEX idx_reviewer_sync_tasks_due ON reviewer_sync_tasks(next_attempt_at);
+CREATE INDEX idx_reviewer_sync_tasks_pr ON reviewer_sync_tasks(pull_request_id, task_id);
+
+-- Tasks that failed for good, kept for inspection and manual retry
+CREATE TABLE reviewer_sync_dead_letters (
+    task_id BIGINT PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    add_reviewer_id VARCHAR(255),
+    remove_reviewer_id VARCHAR(255),
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reviewer_sync_dead_letters_failed ON reviewer_sync_dead_letters(failed_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS reviewer_sync_dead_letters;
+DROP TABLE IF EXISTS reviewer_sync_tasks;